@@ -0,0 +1,62 @@
+package gobom
+
+// maxControlRatio is the ratio of control chars above which content is
+// considered binary
+const maxControlRatio = 0.1
+
+// isTextControl checks if the control char r is common in text
+func isTextControl(r uint32) bool {
+	return r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\b' || r == 0x1B
+}
+
+// LooksBinary checks if buffer looks like binary content rather than text, by
+// examining the first 8 KiB of it: content with NUL chars, or with too many
+// control chars is considered binary.
+//
+// Content that starts with a UTF-16 or UTF-32 BOM is examined by its code
+// units, so the NUL bytes of such text do not make it binary, while random
+// binary content that happens to start with a BOM usually does.
+func LooksBinary(buffer []byte) bool {
+	return looksBinary(buffer, sampleSize)
+}
+
+// looksBinary is LooksBinary for both strings and byte slices, that examines
+// up to limit bytes after the BOM
+func looksBinary[T ~string | ~[]byte](data T, limit int) bool {
+	bomType, size := matchSignatureLen(signatures, data)
+	data = data[size:]
+	if len(data) > limit {
+		data = data[:limit]
+	}
+
+	unit := 1
+	switch bomType {
+	case UTF16LE, UTF16BE:
+		unit = 2
+	case UTF32LE, UTF32BE:
+		unit = 4
+	}
+	bigEndian := bomType.Endianness() == BigEndian
+
+	units, controls := 0, 0
+	for offset := 0; offset+unit <= len(data); offset += unit {
+		var value uint32
+		for i := 0; i < unit; i++ {
+			shift := uint(8 * i)
+			if bigEndian {
+				shift = uint(8 * (unit - 1 - i))
+			}
+			value |= uint32(data[offset+i]) << shift
+		}
+
+		units++
+		switch {
+		case value == 0, value > 0x10FFFF:
+			return true
+		case (value < 0x20 || value == 0x7F) && !isTextControl(value):
+			controls++
+		}
+	}
+
+	return units > 0 && float64(controls)/float64(units) > maxControlRatio
+}
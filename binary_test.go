@@ -0,0 +1,32 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLooksBinary(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   bool
+	}{
+		{"empty", nil, false},
+		{"text", []byte("hello\tworld\r\n"), false},
+		{"utf8 bom", []byte("\xEF\xBB\xBFhello"), false},
+		{"nul", []byte("hello\x00world"), true},
+		{"controls", []byte("\x01\x02\x03\x04abc"), true},
+		{"few controls", append(bytes.Repeat([]byte("a"), 100), 0x01), false},
+		{"utf16le text", []byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}, false},
+		{"utf16le binary", []byte{0xFF, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02}, true},
+		{"utf32be text", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'h'}, false},
+		{"utf32be binary", []byte{0x00, 0x00, 0xFE, 0xFF, 0x7F, 0x45, 0x4C, 0x46}, true},
+		{"elf", []byte("\x7FELF\x02\x01\x01\x00"), true},
+	}
+
+	for _, test := range tests {
+		if got := LooksBinary(test.buffer); got != test.want {
+			t.Errorf("%s: LooksBinary() = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
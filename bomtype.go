@@ -0,0 +1,117 @@
+package gobom
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bomTypeNames holds the human readable name of each BOM type
+var bomTypeNames = map[BOMType]string{
+	Unknown:   "Unknown",
+	UTF8:      "UTF-8",
+	UTF16LE:   "UTF-16LE",
+	UTF16BE:   "UTF-16BE",
+	UTF32LE:   "UTF-32LE",
+	UTF32BE:   "UTF-32BE",
+	GB18030:   "GB18030",
+	UTF7:      "UTF-7",
+	UTF1:      "UTF-1",
+	UTFEBCDIC: "UTF-EBCDIC",
+	SCSU:      "SCSU",
+	BOCU1:     "BOCU-1",
+}
+
+// String is an implementation of fmt.Stringer interface, and returns the name
+// of the BOM type, such as "UTF-8" or "UTF-16LE".
+func (t BOMType) String() string {
+	if name, ok := bomTypeNames[t]; ok {
+		return name
+	}
+
+	return "BOMType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// signatureOf returns the bytes of the BOM of t, or nil if t has no BOM.
+// The returned slice must not be modified.
+func signatureOf(t BOMType) []byte {
+	for _, sig := range signatures {
+		if sig.bomType == t {
+			return sig.bytes
+		}
+	}
+
+	return nil
+}
+
+// Bytes returns a copy of the BOM signature of t, or nil for Unknown.
+// The returned slice can be modified by the caller.
+func (t BOMType) Bytes() []byte {
+	bom := signatureOf(t)
+	if bom == nil {
+		return nil
+	}
+
+	return append([]byte(nil), bom...)
+}
+
+// Len returns the number of bytes the BOM of t takes, or 0 for Unknown.
+//
+// For UTF7 it is the size of UTF7Bom. The other forms of UTF-7 BOM cannot be
+// removed (see UTF7BomVariants), so use BytesToSkip in order to know how many
+// bytes of a given buffer to skip.
+func (t BOMType) Len() int {
+	return len(signatureOf(t))
+}
+
+// normalizeBOMTypeName turns name into lower case, without separators, so
+// "UTF-16LE", "utf_16le" and "utf16le" are considered the same name
+func normalizeBOMTypeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// ParseBOMType returns the BOM type of name. The name is not case sensitive,
+// and can be written with or without separators, such as "utf-8", "UTF16LE"
+// or "utf_32_be".
+func ParseBOMType(name string) (BOMType, error) {
+	normalized := normalizeBOMTypeName(name)
+	for bomType, bomTypeName := range bomTypeNames {
+		if normalizeBOMTypeName(bomTypeName) == normalized {
+			return bomType, nil
+		}
+	}
+
+	return Unknown, fmt.Errorf("gobom: unknown BOM type name %q", name)
+}
+
+// AllBOMTypes returns every BOM type that can be detected, sorted by value.
+// Unknown is not part of the list.
+//
+// The list is built from the known signatures, so it always holds every
+// supported BOM type.
+func AllBOMTypes() []BOMType {
+	seen := make(map[BOMType]bool, len(signatures))
+	types := make([]BOMType, 0, len(signatures))
+	for _, sig := range signatures {
+		if !seen[sig.bomType] {
+			seen[sig.bomType] = true
+			types = append(types, sig.bomType)
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// AllBOMTypesWithUnknown is the same as AllBOMTypes, but Unknown is the first
+// element of the list.
+func AllBOMTypesWithUnknown() []BOMType {
+	return append([]BOMType{Unknown}, AllBOMTypes()...)
+}
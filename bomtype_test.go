@@ -0,0 +1,133 @@
+package gobom
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBOMTypeString(t *testing.T) {
+	tests := []struct {
+		bomType BOMType
+		want    string
+	}{
+		{Unknown, "Unknown"},
+		{UTF8, "UTF-8"},
+		{UTF16LE, "UTF-16LE"},
+		{UTF16BE, "UTF-16BE"},
+		{UTF32LE, "UTF-32LE"},
+		{UTF32BE, "UTF-32BE"},
+		{GB18030, "GB18030"},
+		{UTF7, "UTF-7"},
+		{BOCU1, "BOCU-1"},
+		{SCSU, "SCSU"},
+		{UTFEBCDIC, "UTF-EBCDIC"},
+		{UTF1, "UTF-1"},
+		{BOMType(200), "BOMType(200)"},
+	}
+
+	for _, test := range tests {
+		if got := fmt.Sprint(test.bomType); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestBOMTypeBytes(t *testing.T) {
+	if got := Unknown.Bytes(); got != nil {
+		t.Errorf("Unknown.Bytes() = %v, want nil", got)
+	}
+
+	got := UTF32LE.Bytes()
+	if !bytes.Equal(got, UTF32LEBom) {
+		t.Errorf("UTF32LE.Bytes() = %v, want %v", got, UTF32LEBom)
+	}
+
+	got[0] = 0
+	if UTF32LEBom[0] != 0xFF {
+		t.Error("Bytes() returned the package level signature instead of a copy")
+	}
+}
+
+func TestBOMTypeLen(t *testing.T) {
+	tests := []struct {
+		bomType BOMType
+		want    int
+	}{
+		{Unknown, 0},
+		{UTF8, 3},
+		{UTF16LE, 2},
+		{UTF16BE, 2},
+		{UTF32LE, 4},
+		{UTF32BE, 4},
+		{GB18030, 4},
+		{UTF7, 5},
+		{BOCU1, 3},
+		{SCSU, 3},
+		{UTFEBCDIC, 4},
+		{UTF1, 3},
+	}
+
+	for _, test := range tests {
+		if got := test.bomType.Len(); got != test.want {
+			t.Errorf("%s.Len() = %d, want %d", test.bomType, got, test.want)
+		}
+	}
+}
+
+func TestParseBOMType(t *testing.T) {
+	tests := []struct {
+		name string
+		want BOMType
+	}{
+		{"utf-8", UTF8},
+		{"UTF8", UTF8},
+		{"UTF16LE", UTF16LE},
+		{"utf-16be", UTF16BE},
+		{"utf_32_le", UTF32LE},
+		{" utf-32be ", UTF32BE},
+		{"gb18030", GB18030},
+		{"utf-7", UTF7},
+		{"bocu-1", BOCU1},
+		{"scsu", SCSU},
+		{"utf-ebcdic", UTFEBCDIC},
+		{"utf-1", UTF1},
+		{"unknown", Unknown},
+	}
+
+	for _, test := range tests {
+		got, err := ParseBOMType(test.name)
+		if err != nil {
+			t.Errorf("ParseBOMType(%q): unexpected error: %s", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseBOMType(%q) = %s, want %s", test.name, got, test.want)
+		}
+	}
+
+	for _, name := range []string{"", "utf", "latin1", "utf-16xe"} {
+		if _, err := ParseBOMType(name); err == nil {
+			t.Errorf("ParseBOMType(%q): expected an error", name)
+		}
+	}
+}
+
+func TestAllBOMTypes(t *testing.T) {
+	want := []BOMType{UTF8, UTF16LE, UTF16BE, UTF32LE, UTF32BE, GB18030, UTF7, UTF1, UTFEBCDIC, SCSU, BOCU1}
+	if got := AllBOMTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllBOMTypes() = %v, want %v", got, want)
+	}
+
+	want = append([]BOMType{Unknown}, want...)
+	if got := AllBOMTypesWithUnknown(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllBOMTypesWithUnknown() = %v, want %v", got, want)
+	}
+
+	for _, bomType := range AllBOMTypes() {
+		if _, ok := bomTypeNames[bomType]; !ok {
+			t.Errorf("%d has no name", bomType)
+		}
+	}
+}
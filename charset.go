@@ -0,0 +1,53 @@
+package gobom
+
+import "strings"
+
+// CharsetCheck is the result of cross checking the charset a Content-Type
+// header declares with the BOM of the content
+type CharsetCheck struct {
+	// Declared is the lower case charset of the header, or an empty string
+	// when the header has none
+	Declared string
+	// BOM is the BOM type of the content
+	BOM BOMType
+	// Agree is true when the declared charset and the BOM agree, or when one
+	// of them is missing
+	Agree bool
+	// Encoding is the lower case label of the encoding to use, or an empty
+	// string when neither the header nor the content tell it
+	Encoding string
+	// Source tells what decided Encoding: SourceBOM, SourceHeader, or
+	// SourceDefault when nothing did
+	Source EncodingSource
+}
+
+// CheckCharset detects the BOM of buffer, and checks if it agrees with the
+// charset that the Content-Type header value contentType declares, such as
+// "text/plain; charset=ISO-8859-1".
+//
+// As browsers do, a BOM is authoritative, so the encoding of content that
+// starts with a BOM is the encoding of the BOM even when the header declares
+// another charset. A declared "utf-16" or "utf-32" agrees with both of the
+// byte orders.
+func CheckCharset(contentType string, buffer []byte) CharsetCheck {
+	check := CharsetCheck{
+		Declared: charsetFromContentType(contentType),
+		BOM:      DetectBOMTypeFromBuffer(buffer),
+		Agree:    true,
+	}
+
+	switch {
+	case check.BOM != Unknown:
+		check.Encoding = strings.ToLower(check.BOM.String())
+		check.Source = SourceBOM
+		if check.Declared != "" {
+			check.Agree = encodingAgrees(check.BOM, true, check.Declared) ||
+				normalizeBOMTypeName(check.Declared) == normalizeBOMTypeName(check.BOM.String())
+		}
+	case check.Declared != "":
+		check.Encoding = check.Declared
+		check.Source = SourceHeader
+	}
+
+	return check
+}
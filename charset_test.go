@@ -0,0 +1,67 @@
+package gobom
+
+import "testing"
+
+func TestCheckCharset(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		buffer      []byte
+		want        CharsetCheck
+	}{
+		{
+			"nothing",
+			"text/plain",
+			[]byte("hello"),
+			CharsetCheck{Agree: true, Source: SourceDefault},
+		},
+		{
+			"header only",
+			"text/plain; charset=ISO-8859-1",
+			[]byte("hello"),
+			CharsetCheck{Declared: "iso-8859-1", Agree: true, Encoding: "iso-8859-1", Source: SourceHeader},
+		},
+		{
+			"bom only",
+			"text/plain",
+			[]byte("\xEF\xBB\xBFhello"),
+			CharsetCheck{BOM: UTF8, Agree: true, Encoding: "utf-8", Source: SourceBOM},
+		},
+		{
+			"agree",
+			`text/html; charset="UTF-8"`,
+			[]byte("\xEF\xBB\xBFhello"),
+			CharsetCheck{Declared: "utf-8", BOM: UTF8, Agree: true, Encoding: "utf-8", Source: SourceBOM},
+		},
+		{
+			"latin1 with utf8 bom",
+			"text/plain; charset=ISO-8859-1",
+			[]byte("\xEF\xBB\xBFhello"),
+			CharsetCheck{Declared: "iso-8859-1", BOM: UTF8, Agree: false, Encoding: "utf-8", Source: SourceBOM},
+		},
+		{
+			"utf16 any order",
+			"text/plain; charset=utf-16",
+			[]byte{0xFF, 0xFE, 'h', 0x00},
+			CharsetCheck{Declared: "utf-16", BOM: UTF16LE, Agree: true, Encoding: "utf-16le", Source: SourceBOM},
+		},
+		{
+			"wrong order",
+			"text/plain; charset=utf-16be",
+			[]byte{0xFF, 0xFE, 'h', 0x00},
+			CharsetCheck{Declared: "utf-16be", BOM: UTF16LE, Agree: false, Encoding: "utf-16le", Source: SourceBOM},
+		},
+		{
+			"gb18030",
+			"text/plain; charset=GB18030",
+			[]byte{0x84, 0x31, 0x95, 0x33, 'h'},
+			CharsetCheck{Declared: "gb18030", BOM: GB18030, Agree: true, Encoding: "gb18030", Source: SourceBOM},
+		},
+	}
+
+	for _, test := range tests {
+		if got := CheckCharset(test.contentType, test.buffer); got != test.want {
+			t.Errorf("%s: CheckCharset(%q) = %+v, want %+v", test.name, test.contentType, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package gobom
+
+import "strings"
+
+// DecodeToString detects the BOM of b, and returns the content after it as a
+// string, with the BOM type. UTF-16, UTF-32 and GB18030 content is decoded,
+// and content with a UTF-8 BOM or without a BOM is taken as UTF-8, as is.
+//
+// ErrUnsupportedEncoding is returned for content of any other BOM type.
+// Invalid content, such as an unpaired surrogate, is replaced by U+FFFD.
+func DecodeToString(b []byte) (string, BOMType, error) {
+	content, bomType := TrimBOM(b)
+
+	switch decoder := decoderOf(bomType); {
+	case decoder != nil:
+		s, err := decoder.String(string(content))
+		if err != nil {
+			return "", bomType, err
+		}
+		return s, bomType, nil
+	case bomType != UTF8 && bomType != Unknown:
+		return "", bomType, ErrUnsupportedEncoding
+	}
+
+	return string(content), bomType, nil
+}
+
+// EncodeStringWithBOM encodes s as UTF-8, UTF-16 or UTF-32, as t tells, with
+// the BOM of t at the beginning, such as for files that Windows tools expect
+// to start with a BOM. A BOM char (U+FEFF) that s starts with is not encoded
+// again.
+//
+// ErrUnsupportedEncoding is returned for any other BOM type, including
+// Unknown.
+func EncodeStringWithBOM(s string, t BOMType) ([]byte, error) {
+	enc := t.Encoding()
+	if enc == nil {
+		return nil, ErrUnsupportedEncoding
+	}
+
+	return enc.NewEncoder().Bytes([]byte(strings.TrimPrefix(s, string(BOMRune))))
+}
@@ -0,0 +1,71 @@
+package gobom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecodeToString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		want     string
+		wantType BOMType
+		wantErr  error
+	}{
+		{"empty", nil, "", Unknown, nil},
+		{"no bom", []byte("naïve"), "naïve", Unknown, nil},
+		{"utf8", []byte("\xEF\xBB\xBFnaïve"), "naïve", UTF8, nil},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00, 0xAC, 0x20}, "h€", UTF16LE, nil},
+		{"utf16be", []byte{0xFE, 0xFF, 0xD8, 0x3D, 0xDE, 0x00}, "\U0001F600", UTF16BE, nil},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, "h", UTF32LE, nil},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x20, 0xAC}, "€", UTF32BE, nil},
+		{"unpaired", []byte{0xFF, 0xFE, 0x3D, 0xD8}, "\uFFFD", UTF16LE, nil},
+		{"gb18030", []byte{0x84, 0x31, 0x95, 0x33, 'h', 0xD6, 0xD0}, "h中", GB18030, nil},
+		{"utf1", []byte{0xF7, 0x64, 0x4C, 'h'}, "", UTF1, ErrUnsupportedEncoding},
+	}
+
+	for _, test := range tests {
+		got, bomType, err := DecodeToString(test.input)
+		if got != test.want || bomType != test.wantType || err != test.wantErr {
+			t.Errorf("%s: DecodeToString() = %q, %s, %v, want %q, %s, %v",
+				test.name, got, bomType, err, test.want, test.wantType, test.wantErr)
+		}
+	}
+}
+
+func TestEncodeStringWithBOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		bomType BOMType
+		want    []byte
+		wantErr error
+	}{
+		{"utf8", "h€", UTF8, []byte("\xEF\xBB\xBFh€"), nil},
+		{"utf8 empty", "", UTF8, []byte("\xEF\xBB\xBF"), nil},
+		{"utf8 bom char", "\uFEFFh", UTF8, []byte("\xEF\xBB\xBFh"), nil},
+		{"utf16le", "h€", UTF16LE, []byte{0xFF, 0xFE, 'h', 0x00, 0xAC, 0x20}, nil},
+		{"utf16be pair", "\U0001F600", UTF16BE, []byte{0xFE, 0xFF, 0xD8, 0x3D, 0xDE, 0x00}, nil},
+		{"utf32le", "h", UTF32LE, []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, nil},
+		{"utf32be", "€", UTF32BE, []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x20, 0xAC}, nil},
+		{"unknown", "h", Unknown, nil, ErrUnsupportedEncoding},
+		{"gb18030", "h", GB18030, nil, ErrUnsupportedEncoding},
+	}
+
+	for _, test := range tests {
+		got, err := EncodeStringWithBOM(test.s, test.bomType)
+		if err != test.wantErr || !bytes.Equal(got, test.want) {
+			t.Errorf("%s: EncodeStringWithBOM(%q, %s) = %v, %v, want %v, %v",
+				test.name, test.s, test.bomType, got, err, test.want, test.wantErr)
+		}
+
+		if err == nil {
+			decoded, bomType, _ := DecodeToString(got)
+			if want := strings.TrimPrefix(test.s, "\uFEFF"); decoded != want || bomType != test.bomType {
+				t.Errorf("%s: DecodeToString() = %q, %s, want %q, %s", test.name, decoded, bomType, want, test.bomType)
+			}
+		}
+	}
+}
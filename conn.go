@@ -0,0 +1,80 @@
+package gobom
+
+import (
+	"errors"
+	"net"
+)
+
+// Conn is a net.Conn that removes a BOM from the beginning of the inbound
+// stream of the wrapped connection, for protocols where a peer might send a
+// BOM before its first message. Anything else, including writes, deadlines
+// and Close, is passed to the wrapped connection as is.
+type Conn struct {
+	net.Conn
+	header   [maxBOMLen]byte
+	read     int
+	buffer   []byte
+	err      error
+	bomType  BOMType
+	detected bool
+}
+
+// NewConn creates a new Conn on top of c
+func NewConn(c net.Conn) *Conn {
+	return &Conn{Conn: c}
+}
+
+// BOMType returns the type of BOM that was removed from the inbound stream.
+// Unlike Reader, it does not read by itself, and it is Unknown until Read
+// decided the BOM.
+func (c *Conn) BOMType() BOMType {
+	return c.bomType
+}
+
+// Read is an implementation of io.Reader interface.
+//
+// A read deadline that expires while the BOM is not decided yet, such as when
+// the peer sent only part of it, is returned as is, and the bytes that were
+// read are kept, so the next call of Read continues the detection.
+func (c *Conn) Read(buffer []byte) (int, error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if !c.detected {
+		header, err := readBOM(c.Conn, c.header[:c.read])
+		c.read = len(header)
+		if err != nil && isTimeout(err) && !bomDecided(header) {
+			return 0, err
+		}
+
+		c.detected = true
+		c.bomType = DetectBOMTypeFromBuffer(header)
+		skip := BytesToSkip(header)
+		if skip < 0 {
+			skip = 0
+		}
+		c.buffer = header[skip:]
+		c.err = err
+	}
+
+	if len(c.buffer) > 0 {
+		n := copy(buffer, c.buffer)
+		c.buffer = c.buffer[n:]
+		return n, nil
+	}
+
+	if c.err != nil {
+		err := c.err
+		c.err = nil
+		return 0, err
+	}
+
+	return c.Conn.Read(buffer)
+}
+
+// isTimeout checks if err is a timeout, such as an expired deadline
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,85 @@
+package gobom
+
+import (
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn := NewConn(server)
+	go func() {
+		client.Write([]byte{0xEF, 0xBB})
+		client.Write([]byte{0xBF})
+		client.Write([]byte("hello\n"))
+		client.Close()
+	}()
+
+	got, err := io.ReadAll(conn)
+	if err != nil || string(got) != "hello\n" {
+		t.Errorf("got %q, %v, want %q", got, err, "hello\n")
+	}
+	if conn.BOMType() != UTF8 {
+		t.Errorf("BOMType() = %s, want %s", conn.BOMType(), UTF8)
+	}
+}
+
+func TestConnDeadline(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn := NewConn(server)
+	go client.Write([]byte{0xEF, 0xBB})
+
+	conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	buffer := make([]byte, 16)
+	if _, err := conn.Read(buffer); !os.IsTimeout(err) {
+		t.Fatalf("Read() error = %v, want a timeout", err)
+	}
+
+	conn.SetReadDeadline(time.Time{})
+	go client.Write([]byte{0xBF, 'h', 'i'})
+
+	n, err := conn.Read(buffer)
+	if err != nil || conn.BOMType() != UTF8 {
+		t.Fatalf("Read() = %d, %v, BOM %s, want %s", n, err, conn.BOMType(), UTF8)
+	}
+	got := string(buffer[:n])
+	for len(got) < 2 {
+		n, err = conn.Read(buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got += string(buffer[:n])
+	}
+	if got != "hi" {
+		t.Errorf("got %q, want %q", got, "hi")
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := client.Write([]byte("x")); err != io.ErrClosedPipe {
+		t.Errorf("Write() after Close error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestConnWrite(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn := NewConn(server)
+	defer conn.Close()
+
+	go conn.Write([]byte("\xEF\xBB\xBFout"))
+
+	buffer := make([]byte, 6)
+	if _, err := io.ReadFull(client, buffer); err != nil || string(buffer) != "\xEF\xBB\xBFout" {
+		t.Errorf("got %q, %v, want writes unchanged", buffer, err)
+	}
+}
@@ -0,0 +1,106 @@
+package gobom
+
+import (
+	"context"
+	"io"
+)
+
+// readResult is the result of a read that was made on behalf of a
+// contextReader
+type readResult struct {
+	n   int
+	err error
+}
+
+// contextReader is a reader that stops waiting for the wrapped reader when its
+// context is done, until the bytes it returned decide the BOM. From then on,
+// it reads from the wrapped reader directly.
+type contextReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	header  []byte
+	decided bool
+}
+
+// Read is an implementation of io.Reader interface. The wrapped reader reads
+// into its own buffer, so a read that ends after the context is done does not
+// touch the buffer of the caller.
+func (c *contextReader) Read(buffer []byte) (int, error) {
+	if c.decided {
+		return c.reader.Read(buffer)
+	}
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if c.ctx.Done() == nil {
+		n, err := c.reader.Read(buffer)
+		c.decide(buffer[:n])
+		return n, err
+	}
+
+	own := make([]byte, len(buffer))
+	result := make(chan readResult, 1)
+	go func() {
+		n, err := c.reader.Read(own)
+		result <- readResult{n, err}
+	}()
+
+	select {
+	case <-c.ctx.Done():
+		return 0, c.ctx.Err()
+	case res := <-result:
+		n := copy(buffer, own[:res.n])
+		c.decide(buffer[:n])
+		return n, res.err
+	}
+}
+
+// decide keeps the beginning of p, that was just read, until the bytes that
+// were read decide the BOM
+func (c *contextReader) decide(p []byte) {
+	if room := maxBOMLen - len(c.header); len(p) > room {
+		p = p[:room]
+	}
+	c.header = append(c.header, p...)
+	c.decided = bomDecided(c.header)
+}
+
+// Close is an implementation of io.Closer interface, that closes the wrapped
+// reader when it implements io.Closer
+func (c *contextReader) Close() error {
+	if closer, ok := c.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// DetectBOMTypeFromReaderContext is DetectBOMTypeFromReader that stops waiting
+// for r when ctx is done, and returns the error of ctx, so a server can bound
+// the time it waits for a slow client. It reads no more bytes than needed in
+// order to decide, in the same manner as Reader.
+//
+// A read that r is blocked in cannot be canceled, so it is left to end in the
+// background, and the bytes it returns are dropped. Use it when r is not
+// needed after ctx is done.
+func DetectBOMTypeFromReaderContext(ctx context.Context, r io.Reader) (BOMType, error) {
+	buffer, err := readBOM(&contextReader{ctx: ctx, reader: r}, make([]byte, 0, maxBOMLen))
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+
+	return DetectBOMTypeFromBuffer(buffer), nil
+}
+
+// NewReaderContext creates a new Reader on top of r, and configures it with
+// opts, that stops waiting for r when ctx is done while the BOM is detected,
+// and returns the error of ctx. As with DetectBOMTypeFromReaderContext, a read
+// that r is blocked in is left to end in the background. Once the BOM is
+// decided, the content is read from r directly, and ctx is not checked
+// anymore.
+//
+// Close closes r when it implements io.Closer, but the returned Reader cannot
+// seek.
+func NewReaderContext(ctx context.Context, r io.Reader, opts ...ReaderOption) *Reader {
+	return NewReader(&contextReader{ctx: ctx, reader: r}, opts...)
+}
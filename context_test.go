@@ -0,0 +1,77 @@
+package gobom
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDetectBOMTypeFromReaderContext(t *testing.T) {
+	got, err := DetectBOMTypeFromReaderContext(context.Background(), bytes.NewReader([]byte("\xEF\xBB\xBFhi")))
+	if got != UTF8 || err != nil {
+		t.Errorf("DetectBOMTypeFromReaderContext() = %s, %v, want %s", got, err, UTF8)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	got, err = DetectBOMTypeFromReaderContext(ctx, bytes.NewReader([]byte{0xFF, 0xFE}))
+	if got != UTF16LE || err != nil {
+		t.Errorf("DetectBOMTypeFromReaderContext() = %s, %v, want %s", got, err, UTF16LE)
+	}
+	cancel()
+
+	if _, err := DetectBOMTypeFromReaderContext(ctx, bytes.NewReader([]byte("hi"))); err != context.Canceled {
+		t.Errorf("DetectBOMTypeFromReaderContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	// a peer that sent part of a BOM and waits
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte{0xFF, 0xFE})
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := DetectBOMTypeFromReaderContext(ctx, pr); err != context.DeadlineExceeded {
+		t.Errorf("DetectBOMTypeFromReaderContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNewReaderContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := NewReaderContext(ctx, bytes.NewReader([]byte("\xEF\xBB\xBFhello")))
+	got, err := io.ReadAll(reader)
+	if err != nil || string(got) != "hello" {
+		t.Errorf("got %q, %v, want %q", got, err, "hello")
+	}
+
+	// the content after the BOM is not bound to ctx
+	detected, stop := context.WithCancel(context.Background())
+	reader = NewReaderContext(detected, bytes.NewReader([]byte("\xEF\xBB\xBFhello")))
+	if _, err := reader.DetectNow(); err != nil {
+		t.Fatalf("DetectNow() error = %v", err)
+	}
+	stop()
+	got, err = io.ReadAll(reader)
+	if err != nil || string(got) != "hello" {
+		t.Errorf("got %q, %v after cancel, want %q", got, err, "hello")
+	}
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	reader = NewReaderContext(ctx, pr)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := reader.Read(make([]byte, 8)); err != context.Canceled {
+		t.Errorf("Read() error = %v, want %v", err, context.Canceled)
+	}
+
+	source := &closeRecorder{Reader: bytes.NewReader(nil)}
+	if err := NewReaderContext(context.Background(), source).Close(); err != nil || !source.closed {
+		t.Errorf("Close() = %v, closed %t, want closed", err, source.closed)
+	}
+}
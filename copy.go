@@ -0,0 +1,29 @@
+package gobom
+
+import "io"
+
+// CopyPreservingBOM copies src to dst in the same manner as io.Copy, while
+// making sure dst starts with the same BOM src starts with, byte for byte, and
+// returns the BOM type of src. The BOM is detected and written by itself, before the rest
+// of the content is copied, so dst can be a writer that transforms the content
+// without changing the encoding signature of the file.
+//
+// The returned number of bytes includes the BOM. Reaching the end of src is
+// not an error, in the same manner as io.Copy.
+func CopyPreservingBOM(dst io.Writer, src io.Reader) (BOMType, int64, error) {
+	reader := NewReader(src)
+	bomType, err := reader.DetectNow()
+	if err != nil {
+		return Unknown, 0, err
+	}
+
+	// the bytes of the BOM as src has them, which the Reader skipped
+	n, err := dst.Write(reader.header[:reader.skip])
+	written := int64(n)
+	if err != nil {
+		return bomType, written, err
+	}
+
+	copied, err := io.Copy(dst, reader)
+	return bomType, written + copied, err
+}
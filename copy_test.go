@@ -0,0 +1,48 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCopyPreservingBOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    BOMType
+	}{
+		{"utf8", "\xEF\xBB\xBFhello", UTF8},
+		{"bom only", "\xEF\xBB\xBF", UTF8},
+		{"utf16le", "\xFF\xFEh\x00", UTF16LE},
+		{"utf7", "+/v8-hello", UTF7},
+		{"utf7 variant", "+/v9AGE-", UTF7},
+		{"no bom", "hello", Unknown},
+		{"empty", "", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			bomType, n, err := CopyPreservingBOM(&out, iotest.OneByteReader(strings.NewReader(tt.content)))
+			if bomType != tt.want || err != nil {
+				t.Errorf("CopyPreservingBOM() = %s, %v, want %s", bomType, err, tt.want)
+			}
+			if out.String() != tt.content || n != int64(len(tt.content)) {
+				t.Errorf("got %q (%d bytes), want %q", out.String(), n, tt.content)
+			}
+		})
+	}
+}
+
+func TestCopyPreservingBOMError(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	var out bytes.Buffer
+	bomType, n, err := CopyPreservingBOM(&out, iotest.ErrReader(errBroken))
+	if bomType != Unknown || n != 0 || err != errBroken {
+		t.Errorf("CopyPreservingBOM() = %s, %d, %v, want %s, 0, %v", bomType, n, err, Unknown, errBroken)
+	}
+}
@@ -0,0 +1,13 @@
+package gobom
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// NewCSVReader creates a new csv.Reader on top of r, that removes its BOM, and
+// decodes UTF-16 and UTF-32 content to UTF-8, in the same manner as
+// NewUTF8Reader, so the name of the first column does not start with U+FEFF.
+func NewCSVReader(r io.Reader) *csv.Reader {
+	return csv.NewReader(NewUTF8Reader(r))
+}
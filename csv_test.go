@@ -0,0 +1,41 @@
+package gobom
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNewCSVReader(t *testing.T) {
+	want := [][]string{{"name", "age"}, {"café", "30"}}
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"utf8", []byte("\xEF\xBB\xBFname,age\ncafé,30\n")},
+		{"no bom", []byte("name,age\ncafé,30\n")},
+		{"utf16le", mustEncode(t, "name,age\ncafé,30\n", UTF16LE)},
+		{"utf32be", mustEncode(t, "name,age\ncafé,30\n", UTF32BE)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCSVReader(bytes.NewReader(tt.content)).ReadAll()
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadAll() = %q, %v, want %q", got, err, want)
+			}
+		})
+	}
+}
+
+// mustEncode encodes s with the BOM of bomType, and fails the test if it cannot
+func mustEncode(t *testing.T, s string, bomType BOMType) []byte {
+	t.Helper()
+
+	encoded, err := EncodeStringWithBOM(s, bomType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
@@ -0,0 +1,95 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+)
+
+// DeferredWriter is an io.Writer that holds the content that is written to it
+// until the BOM to write is known, for code that learns the target encoding
+// only after it started to produce the content.
+type DeferredWriter struct {
+	target  io.Writer
+	buffer  bytes.Buffer
+	writer  *Writer
+	opts    []WriterOption
+	decided bool
+	bomType BOMType
+}
+
+// NewDeferredWriter creates a new DeferredWriter on top of w. The writer the
+// content is passed to once the BOM is decided is configured with opts, in the
+// same manner as NewWriter.
+func NewDeferredWriter(w io.Writer, opts ...WriterOption) *DeferredWriter {
+	return &DeferredWriter{target: w, opts: opts}
+}
+
+// SetBOM decides the BOM to write, and writes it with the content that was
+// held, if any. From then on, the content is written as it comes. If t is
+// Unknown, no BOM is written.
+//
+// ErrBOMDecided is returned when the BOM was already decided, by SetBOM or by
+// Flush, to another BOM type.
+func (d *DeferredWriter) SetBOM(t BOMType) error {
+	if d.decided {
+		if d.bomType != t {
+			return ErrBOMDecided
+		}
+		return nil
+	}
+
+	d.decided = true
+	d.bomType = t
+	d.writer = NewWriter(d.target, t, d.opts...)
+	if d.buffer.Len() == 0 {
+		return nil
+	}
+
+	_, err := d.buffer.WriteTo(d.writer)
+	return err
+}
+
+// BOMType returns the BOM type that was decided, or Unknown when it was not
+// decided yet.
+func (d *DeferredWriter) BOMType() BOMType {
+	return d.bomType
+}
+
+// Buffered returns the number of bytes that are held until the BOM is
+// decided.
+func (d *DeferredWriter) Buffered() int {
+	return d.buffer.Len()
+}
+
+// Write is an implementation of io.Writer interface. Until the BOM is
+// decided, p is held in memory.
+func (d *DeferredWriter) Write(p []byte) (int, error) {
+	if !d.decided {
+		return d.buffer.Write(p)
+	}
+
+	return d.writer.Write(p)
+}
+
+// Flush decides that there is no BOM, when it was not decided yet, and writes
+// the content that was held.
+func (d *DeferredWriter) Flush() error {
+	if !d.decided {
+		if err := d.SetBOM(Unknown); err != nil {
+			return err
+		}
+	}
+
+	return d.writer.Flush()
+}
+
+// Close is an implementation of io.Closer interface. It flushes d, and then
+// closes w when it implements io.Closer.
+func (d *DeferredWriter) Close() error {
+	err := d.Flush()
+	if closeErr := d.writer.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,80 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDeferredWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewDeferredWriter(&out)
+
+	w.Write([]byte("a,b\n"))
+	w.Write([]byte("c,d\n"))
+	if out.Len() != 0 || w.Buffered() != 8 {
+		t.Fatalf("wrote %q and holds %d bytes before the BOM was decided", out.String(), w.Buffered())
+	}
+	if w.BOMType() != Unknown {
+		t.Errorf("BOMType() = %s, want %s", w.BOMType(), Unknown)
+	}
+
+	if err := w.SetBOM(UTF8); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("e,f\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "\xEF\xBB\xBFa,b\nc,d\ne,f\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+	if w.BOMType() != UTF8 || w.Buffered() != 0 {
+		t.Errorf("BOMType() = %s, Buffered() = %d, want %s, 0", w.BOMType(), w.Buffered(), UTF8)
+	}
+}
+
+func TestDeferredWriterSetBOM(t *testing.T) {
+	var out bytes.Buffer
+	w := NewDeferredWriter(&out)
+
+	if err := w.SetBOM(UTF16LE); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetBOM(UTF16LE); err != nil {
+		t.Errorf("SetBOM() of the same type error = %v", err)
+	}
+	if err := w.SetBOM(UTF8); err != ErrBOMDecided {
+		t.Errorf("SetBOM() error = %v, want %v", err, ErrBOMDecided)
+	}
+
+	w.Write([]byte("a\x00"))
+	if want := "\xFF\xFEa\x00"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestDeferredWriterFlushWithoutBOM(t *testing.T) {
+	var out bytes.Buffer
+	w := NewDeferredWriter(&out)
+
+	w.Write([]byte("a"))
+	if err := w.Flush(); err != nil || out.String() != "a" {
+		t.Errorf("Flush() = %v, wrote %q, want %q", err, out.String(), "a")
+	}
+	if err := w.SetBOM(UTF8); err != ErrBOMDecided {
+		t.Errorf("SetBOM() after Flush error = %v, want %v", err, ErrBOMDecided)
+	}
+}
+
+func TestDeferredWriterRejectExistingBOM(t *testing.T) {
+	var out bytes.Buffer
+	w := NewDeferredWriter(&out, WithRejectExistingBOM())
+
+	w.Write([]byte("\xEF\xBB\xBFa"))
+	var existing *ExistingBOMError
+	if err := w.SetBOM(UTF8); !errors.As(err, &existing) {
+		t.Errorf("SetBOM() error = %v, want *ExistingBOMError", err)
+	}
+}
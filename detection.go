@@ -0,0 +1,71 @@
+package gobom
+
+import "bytes"
+
+// Detection holds the result of a BOM detection
+type Detection struct {
+	// Type is the type of BOM that was found, or Unknown
+	Type BOMType
+	// Length is the number of bytes the BOM takes, or 0 if no BOM was found,
+	// or the BOM cannot be removed from the content (see UTF7BomVariants)
+	Length int
+	// BOM holds the bytes of the BOM that was found. It is a sub slice of the
+	// buffer that was detected, and nil if no BOM was found, or the BOM cannot
+	// be removed from the content.
+	BOM []byte
+	// TooShort is true when the buffer is too short to decide, because it is
+	// the beginning of a longer BOM that might have been found if the buffer
+	// was longer.
+	TooShort bool
+	// Binary is true when the content looks binary (see LooksBinary). It is
+	// set only by a Detector that checks for binary content.
+	Binary bool
+}
+
+// Detect detects the BOM of buffer, and returns the full information about it.
+// When more than one BOM matches, the longest one is used.
+func Detect(buffer []byte) Detection {
+	return matchDetection(signatures, buffer)
+}
+
+// matchDetection returns the Detection of the first signature in sigs that
+// buffer starts with
+func matchDetection(sigs []signature, buffer []byte) Detection {
+	detection := Detection{TooShort: isTooShort(sigs, buffer)}
+
+	for _, sig := range sigs {
+		if bytes.HasPrefix(buffer, sig.bytes) {
+			detection.Type = sig.bomType
+			if sig.removable() {
+				detection.Length = len(sig.bytes)
+				detection.BOM = buffer[:len(sig.bytes)]
+			}
+			break
+		}
+	}
+
+	return detection
+}
+
+// isTooShort returns true when data is shorter than one of sigs, and is the
+// beginning of it, so a longer data might have matched it
+func isTooShort[T ~string | ~[]byte](sigs []signature, data T) bool {
+	for _, sig := range sigs {
+		if len(data) >= len(sig.bytes) {
+			continue
+		}
+
+		match := true
+		for i := 0; i < len(data); i++ {
+			if data[i] != sig.bytes[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package gobom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   Detection
+	}{
+		{"empty", nil, Detection{TooShort: true}},
+		{"no bom", []byte("hello"), Detection{}},
+		{"partial utf8", []byte{0xEF, 0xBB}, Detection{TooShort: true}},
+		{
+			"utf8",
+			[]byte{0xEF, 0xBB, 0xBF, 'a'},
+			Detection{Type: UTF8, Length: 3, BOM: []byte{0xEF, 0xBB, 0xBF}},
+		},
+		{
+			"utf16le or utf32le",
+			[]byte{0xFF, 0xFE, 0x00},
+			Detection{Type: UTF16LE, Length: 2, BOM: []byte{0xFF, 0xFE}, TooShort: true},
+		},
+		{
+			"utf32le",
+			[]byte{0xFF, 0xFE, 0x00, 0x00},
+			Detection{Type: UTF32LE, Length: 4, BOM: []byte{0xFF, 0xFE, 0x00, 0x00}},
+		},
+		{
+			"utf7",
+			[]byte("+/v8-a"),
+			Detection{Type: UTF7, Length: 5, BOM: []byte("+/v8-")},
+		},
+		{"utf7 variant", []byte("+/v9AGE-"), Detection{Type: UTF7}},
+	}
+
+	for _, test := range tests {
+		got := Detect(test.buffer)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
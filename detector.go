@@ -0,0 +1,351 @@
+package gobom
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// firstDynamicBOMType is the value of the first BOM type that is registered at
+// a Detector. The values below it are kept for the BOM types of the package.
+const firstDynamicBOMType BOMType = 128
+
+// Detector detects BOM types using the signatures of the package, and
+// additional signatures that were registered at it.
+//
+// The registered signatures are known only to the Detector they were
+// registered at, and the package level functions are not affected by them.
+// A Detector is safe for concurrent use.
+type Detector struct {
+	mutex      sync.RWMutex
+	signatures []signature
+	names      map[BOMType]string
+	next       BOMType
+	minBytes   int
+	strict     bool
+	priority   []BOMType
+	binary     BinaryPolicy
+	sniffLimit int
+}
+
+// BinaryPolicy controls what a Detector does with content that looks binary
+type BinaryPolicy uint8
+
+// Enumeration of the binary policies
+const (
+	// BinaryIgnore does not check if the content is binary
+	BinaryIgnore BinaryPolicy = iota
+	// BinaryFlag sets Detection.Binary when the content looks binary, and
+	// detects its BOM as usual
+	BinaryFlag
+	// BinarySkip reports Unknown for content that looks binary, so random
+	// binary content that starts with the bytes of a BOM is not reported as
+	// text. Detection.Binary is set as well.
+	BinarySkip
+)
+
+// DetectorOption configures a Detector
+type DetectorOption func(*Detector)
+
+// Signature is a named signature to register at a Detector
+type Signature struct {
+	Name  string
+	Bytes []byte
+}
+
+// WithMinBytes makes the Detector report Unknown for content that is shorter
+// than n bytes, in the same manner as DetectBOMTypeFromBytes does with 5 bytes.
+func WithMinBytes(n int) DetectorOption {
+	return func(d *Detector) {
+		d.minBytes = n
+	}
+}
+
+// WithStrictLength makes the Detector report Unknown when the content is too
+// short to decide, because it might be the beginning of a longer signature.
+// For example FF FE is reported as Unknown, because it might be the beginning
+// of UTF32LE BOM.
+func WithStrictLength() DetectorOption {
+	return func(d *Detector) {
+		d.strict = true
+	}
+}
+
+// WithSignaturePriority makes the Detector check the signatures of types
+// before any other signature, in the given order, instead of preferring the
+// longest signature. For example WithSignaturePriority(UTF16LE) reports
+// FF FE 00 00 as UTF16LE.
+func WithSignaturePriority(types ...BOMType) DetectorOption {
+	return func(d *Detector) {
+		d.priority = append([]BOMType(nil), types...)
+	}
+}
+
+// WithExtraSignatures registers sigs at the Detector, in the same manner as
+// Register. The BOM types that were given to them are available by using
+// Lookup.
+//
+// NewDetector panics if any of sigs cannot be registered, as it is a
+// programming error.
+func WithExtraSignatures(sigs ...Signature) DetectorOption {
+	return func(d *Detector) {
+		for _, sig := range sigs {
+			if _, err := d.Register(sig.Name, sig.Bytes); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// WithBinaryPolicy makes the Detector check if the content looks binary, and
+// handle it according to policy. Since the check needs more than the BOM, a
+// Detector with a policy examines up to 8 KiB after the BOM, or the limit that
+// was set by WithDetectorSniffLimit, and reads them in DetectReader.
+func WithBinaryPolicy(policy BinaryPolicy) DetectorOption {
+	return func(d *Detector) {
+		d.binary = policy
+	}
+}
+
+// WithDetectorSniffLimit sets the number of bytes after the BOM a Detector
+// with a binary policy examines, and reads in DetectReader, to n, instead of 8
+// KiB, in the same manner as WithSniffLimit does for the heuristics. A limit
+// that is not positive keeps the default.
+func WithDetectorSniffLimit(n int) DetectorOption {
+	return func(d *Detector) {
+		if n > 0 {
+			d.sniffLimit = n
+		}
+	}
+}
+
+// NewDetector creates a new Detector that knows the BOM types of the package,
+// and configures it with opts.
+func NewDetector(opts ...DetectorOption) *Detector {
+	d := &Detector{
+		signatures: append([]signature(nil), signatures...),
+		names:      make(map[BOMType]string, len(bomTypeNames)),
+		next:       firstDynamicBOMType,
+		sniffLimit: sampleSize,
+	}
+
+	for bomType, name := range bomTypeNames {
+		d.names[bomType] = name
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.sortSignatures()
+
+	return d
+}
+
+// sortSignatures orders the signatures by their priority, and then from the
+// longest to the shortest one. The order of signatures with the same priority
+// and size is kept.
+func (d *Detector) sortSignatures() {
+	rank := func(t BOMType) int {
+		for i, bomType := range d.priority {
+			if bomType == t {
+				return i
+			}
+		}
+		return len(d.priority)
+	}
+
+	sort.SliceStable(d.signatures, func(i, j int) bool {
+		a, b := d.signatures[i], d.signatures[j]
+		rankA, rankB := rank(a.bomType), rank(b.bomType)
+		if rankA != rankB {
+			return rankA < rankB
+		}
+		return len(a.bytes) > len(b.bytes)
+	})
+}
+
+// accept checks if content with size bytes can be detected by d
+func (d *Detector) accept(size int, tooShort, binary bool) bool {
+	if size < d.minBytes || (binary && d.binary == BinarySkip) {
+		return false
+	}
+
+	return !d.strict || !tooShort
+}
+
+// isBinary checks if data looks binary, when d checks for it
+func isBinary[T ~string | ~[]byte](d *Detector, data T) bool {
+	return d.binary != BinaryIgnore && looksBinary(data, d.sniffLimit)
+}
+
+// Register adds a new signature to d, and returns the new BOM type that is
+// reported when content starts with it. The signature is copied.
+//
+// Registered signatures take part in all the detection methods of d, and as
+// with the BOMs of the package, the longest matching signature wins.
+// The name must be unique (not case sensitive), and the signature must not be
+// empty.
+func (d *Detector) Register(name string, sig []byte) (BOMType, error) {
+	if len(sig) == 0 {
+		return Unknown, fmt.Errorf("gobom: empty signature for %q", name)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	normalized := normalizeBOMTypeName(name)
+	for _, existing := range d.names {
+		if normalizeBOMTypeName(existing) == normalized {
+			return Unknown, fmt.Errorf("gobom: BOM type %q is already registered", name)
+		}
+	}
+
+	if d.next < firstDynamicBOMType {
+		return Unknown, fmt.Errorf("gobom: cannot register %q, no BOM types are left", name)
+	}
+
+	bomType := d.next
+	d.next++
+	d.names[bomType] = name
+
+	d.signatures = append(d.signatures, signature{bomType: bomType, bytes: append([]byte(nil), sig...)})
+	d.sortSignatures()
+
+	return bomType, nil
+}
+
+// Lookup returns the BOM type of name (not case sensitive), including the
+// names of registered BOM types.
+func (d *Detector) Lookup(name string) (BOMType, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	normalized := normalizeBOMTypeName(name)
+	for bomType, existing := range d.names {
+		if normalizeBOMTypeName(existing) == normalized {
+			return bomType, true
+		}
+	}
+
+	return Unknown, false
+}
+
+// Name returns the name of t, including the names of registered BOM types.
+func (d *Detector) Name(t BOMType) string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if name, ok := d.names[t]; ok {
+		return name
+	}
+
+	return t.String()
+}
+
+// Bytes returns a copy of the signature of t, or nil if t is not known to d.
+func (d *Detector) Bytes(t BOMType) []byte {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	for _, sig := range d.signatures {
+		if sig.bomType == t {
+			return append([]byte(nil), sig.bytes...)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of bytes the signature of t takes, or 0 if t is not
+// known to d.
+func (d *Detector) Len(t BOMType) int {
+	return len(d.Bytes(t))
+}
+
+// readSize returns the number of bytes d needs in order to detect a BOM, which
+// is the size of the longest signature, or the minimum size of the content.
+// With a binary policy, the sniff limit is needed after the BOM as well.
+func (d *Detector) readSize() int {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	longest := 0
+	for _, sig := range d.signatures {
+		if len(sig.bytes) > longest {
+			longest = len(sig.bytes)
+		}
+	}
+
+	size := longest
+	if d.binary != BinaryIgnore {
+		size += d.sniffLimit
+	}
+	if d.minBytes > size {
+		size = d.minBytes
+	}
+
+	return size
+}
+
+// DetectBOMType detects the BOM type of buffer.
+// When more than one signature matches, the longest one is returned, unless
+// the priority of the signatures was changed.
+func (d *Detector) DetectBOMType(buffer []byte) BOMType {
+	return d.Detect(buffer).Type
+}
+
+// DetectString detects the BOM type of s, without copying it.
+func (d *Detector) DetectString(s string) BOMType {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if !d.accept(len(s), isTooShort(d.signatures, s), isBinary(d, s)) {
+		return Unknown
+	}
+
+	return matchSignature(d.signatures, s)
+}
+
+// DetectAllCandidates returns every BOM type that buffer starts with, ordered
+// from the longest signature to the shortest one, unless the priority of the
+// signatures was changed.
+func (d *Detector) DetectAllCandidates(buffer []byte) []BOMType {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if !d.accept(len(buffer), isTooShort(d.signatures, buffer), isBinary(d, buffer)) {
+		return []BOMType{}
+	}
+
+	return matchCandidates(d.signatures, buffer)
+}
+
+// Detect detects the BOM of buffer, and returns the full information about it.
+func (d *Detector) Detect(buffer []byte) Detection {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	detection := matchDetection(d.signatures, buffer)
+	detection.Binary = isBinary(d, buffer)
+	if !d.accept(len(buffer), detection.TooShort, detection.Binary) {
+		return Detection{
+			TooShort: detection.TooShort || len(buffer) < d.minBytes,
+			Binary:   detection.Binary,
+		}
+	}
+
+	return detection
+}
+
+// DetectReader reads from r the number of bytes d needs in order to detect a
+// BOM, and detects the BOM type out of them.
+// As with DetectBOMTypeFromReader, reaching the end of r is not an error.
+func (d *Detector) DetectReader(r io.Reader) (BOMType, error) {
+	buffer, err := readHeader(r, d.readSize())
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+
+	return d.DetectBOMType(buffer), nil
+}
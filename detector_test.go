@@ -0,0 +1,227 @@
+package gobom
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectorRegister(t *testing.T) {
+	d := NewDetector()
+
+	custom, err := d.Register("custom", []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x01})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if custom < firstDynamicBOMType {
+		t.Errorf("got %d, want at least %d", custom, firstDynamicBOMType)
+	}
+
+	short, err := d.Register("short", []byte{0xFF})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if short == custom {
+		t.Errorf("both registered types got %d", short)
+	}
+
+	if _, err := d.Register("CUSTOM", []byte{0x01}); err == nil {
+		t.Error("expected an error for a duplicated name")
+	}
+	if _, err := d.Register("utf-8", []byte{0x01}); err == nil {
+		t.Error("expected an error for a name of the package")
+	}
+	if _, err := d.Register("empty", nil); err == nil {
+		t.Error("expected an error for an empty signature")
+	}
+
+	tests := []struct {
+		buffer []byte
+		want   BOMType
+	}{
+		{[]byte{0xCA, 0xFE, 0xBA, 0xBE, 0x01, 'a'}, custom},
+		{[]byte{0xCA, 0xFE, 0xBA, 0xBE}, Unknown},
+		{[]byte{0xFF, 'a'}, short},
+		{[]byte{0xFF, 0xFE, 'a', 0x00}, UTF16LE},
+		{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+	}
+
+	for _, test := range tests {
+		if got := d.DetectBOMType(test.buffer); got != test.want {
+			t.Errorf("DetectBOMType(%v) = %s, want %s", test.buffer, d.Name(got), d.Name(test.want))
+		}
+		if got := d.DetectString(string(test.buffer)); got != test.want {
+			t.Errorf("DetectString(%v) = %s, want %s", test.buffer, d.Name(got), d.Name(test.want))
+		}
+		got, err := d.DetectReader(iotest.OneByteReader(bytes.NewReader(test.buffer)))
+		if err != nil || got != test.want {
+			t.Errorf("DetectReader(%v) = %s, %v, want %s", test.buffer, d.Name(got), err, d.Name(test.want))
+		}
+	}
+
+	got := d.DetectAllCandidates([]byte{0xFF, 0xFE, 0x00, 0x00})
+	if want := []BOMType{UTF32LE, UTF16LE, short}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectAllCandidates() = %v, want %v", got, want)
+	}
+
+	detection := d.Detect([]byte{0xCA, 0xFE, 0xBA, 0xBE, 0x01})
+	if detection.Type != custom || detection.Length != 5 {
+		t.Errorf("Detect() = %+v", detection)
+	}
+
+	if name := d.Name(custom); name != "custom" {
+		t.Errorf("Name() = %q, want %q", name, "custom")
+	}
+	if size := d.Len(custom); size != 5 {
+		t.Errorf("Len() = %d, want 5", size)
+	}
+
+	if got := DetectBOMTypeFromBuffer([]byte{0xFF, 'a'}); got != Unknown {
+		t.Errorf("package detection was changed by a Detector: %s", got)
+	}
+}
+
+func TestDetectorRegisterLimit(t *testing.T) {
+	d := NewDetector()
+	for i := int(firstDynamicBOMType); i <= 255; i++ {
+		if _, err := d.Register(strconv.Itoa(i), []byte{byte(i)}); err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+	}
+
+	if _, err := d.Register("one too many", []byte{0x01}); err == nil {
+		t.Error("expected an error when no BOM types are left")
+	}
+}
+
+func TestDetectorOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []DetectorOption
+		buffer []byte
+		want   BOMType
+	}{
+		{"default", nil, []byte{0xEF, 0xBB, 0xBF}, UTF8},
+		{"min bytes short", []DetectorOption{WithMinBytes(5)}, []byte{0xEF, 0xBB, 0xBF}, Unknown},
+		{"min bytes", []DetectorOption{WithMinBytes(5)}, []byte{0xEF, 0xBB, 0xBF, 'a', 'b'}, UTF8},
+		{"not strict", nil, []byte{0xFF, 0xFE}, UTF16LE},
+		{"strict", []DetectorOption{WithStrictLength()}, []byte{0xFF, 0xFE}, Unknown},
+		{"strict decided", []DetectorOption{WithStrictLength()}, []byte{0xFF, 0xFE, 'a'}, UTF16LE},
+		{"strict utf8", []DetectorOption{WithStrictLength()}, []byte{0xEF, 0xBB, 0xBF}, UTF8},
+		{"longest", nil, []byte{0xFF, 0xFE, 0x00, 0x00}, UTF32LE},
+		{"priority", []DetectorOption{WithSignaturePriority(UTF16LE)}, []byte{0xFF, 0xFE, 0x00, 0x00}, UTF16LE},
+	}
+
+	for _, test := range tests {
+		d := NewDetector(test.opts...)
+		if got := d.DetectBOMType(test.buffer); got != test.want {
+			t.Errorf("%s: DetectBOMType(%v) = %s, want %s", test.name, test.buffer, got, test.want)
+		}
+		if got := d.DetectString(string(test.buffer)); got != test.want {
+			t.Errorf("%s: DetectString(%v) = %s, want %s", test.name, test.buffer, got, test.want)
+		}
+		got, err := d.DetectReader(bytes.NewReader(test.buffer))
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectReader(%v) = %s, %v, want %s", test.name, test.buffer, got, err, test.want)
+		}
+	}
+}
+
+func TestDetectorExtraSignatures(t *testing.T) {
+	d := NewDetector(WithExtraSignatures(
+		Signature{Name: "private", Bytes: []byte{0x01, 0x02}},
+		Signature{Name: "internal", Bytes: []byte{0x01, 0x02, 0x03}},
+	))
+
+	private, ok := d.Lookup("PRIVATE")
+	if !ok {
+		t.Fatal("private signature was not registered")
+	}
+	internal, ok := d.Lookup("internal")
+	if !ok {
+		t.Fatal("internal signature was not registered")
+	}
+
+	if got := d.DetectBOMType([]byte{0x01, 0x02, 'a'}); got != private {
+		t.Errorf("got %s, want %s", d.Name(got), d.Name(private))
+	}
+	if got := d.DetectBOMType([]byte{0x01, 0x02, 0x03}); got != internal {
+		t.Errorf("got %s, want %s", d.Name(got), d.Name(internal))
+	}
+	if _, ok := d.Lookup("missing"); ok {
+		t.Error("Lookup() found a missing name")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an invalid signature")
+		}
+	}()
+	NewDetector(WithExtraSignatures(Signature{Name: "empty"}))
+}
+
+func TestDetectorBinaryPolicy(t *testing.T) {
+	binary := []byte{0xFF, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02}
+	text := []byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}
+
+	tests := []struct {
+		name       string
+		policy     BinaryPolicy
+		buffer     []byte
+		want       BOMType
+		wantBinary bool
+	}{
+		{"ignore", BinaryIgnore, binary, UTF32LE, false},
+		{"flag", BinaryFlag, binary, UTF32LE, true},
+		{"skip", BinarySkip, binary, Unknown, true},
+		{"skip text", BinarySkip, text, UTF16LE, false},
+	}
+
+	for _, test := range tests {
+		d := NewDetector(WithBinaryPolicy(test.policy))
+
+		detection := d.Detect(test.buffer)
+		if detection.Type != test.want || detection.Binary != test.wantBinary {
+			t.Errorf("%s: Detect() = %+v, want %s, binary %t", test.name, detection, test.want, test.wantBinary)
+		}
+		if got := d.DetectString(string(test.buffer)); got != test.want {
+			t.Errorf("%s: DetectString() = %s, want %s", test.name, got, test.want)
+		}
+		got, err := d.DetectReader(bytes.NewReader(test.buffer))
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectReader() = %s, %v, want %s", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestDetectorSniffLimit(t *testing.T) {
+	buffer := append([]byte{0xEF, 0xBB, 0xBF}, bytes.Repeat([]byte("text "), 20)...)
+	buffer = append(buffer, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02)
+
+	tests := []struct {
+		name       string
+		limit      int
+		want       BOMType
+		wantBinary bool
+	}{
+		{"default", 0, Unknown, true},
+		{"negative", -1, Unknown, true},
+		{"before binary", 16, UTF8, false},
+		{"covers binary", 200, Unknown, true},
+	}
+
+	for _, test := range tests {
+		d := NewDetector(WithBinaryPolicy(BinarySkip), WithDetectorSniffLimit(test.limit))
+
+		detection := d.Detect(buffer)
+		if detection.Type != test.want || detection.Binary != test.wantBinary {
+			t.Errorf("%s: Detect() = %+v, want %s, binary %t", test.name, detection, test.want, test.wantBinary)
+		}
+		got, err := d.DetectReader(bytes.NewReader(buffer))
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectReader() = %s, %v, want %s", test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package gobom
+
+// Reason explains the result of DetectWithDiagnostics
+type Reason uint8
+
+// Enumeration of the reasons for a detection result
+const (
+	// Found means that a BOM was found
+	Found Reason = iota
+	// TooShort means that the buffer is shorter than the shortest BOM, and
+	// therefore cannot hold one
+	TooShort
+	// PartialBOM means that the buffer holds the beginning of a BOM, but ended
+	// before the BOM was complete
+	PartialBOM
+	// NoMatch means that the buffer does not start with a BOM
+	NoMatch
+)
+
+// String is an implementation of fmt.Stringer interface
+func (r Reason) String() string {
+	switch r {
+	case Found:
+		return "BOM found"
+	case TooShort:
+		return "buffer is too short"
+	case PartialBOM:
+		return "partial BOM"
+	case NoMatch:
+		return "no BOM"
+	}
+
+	return "unknown reason"
+}
+
+// DetectWithDiagnostics detects the BOM type of buffer, and explains the
+// result, so when Unknown is returned, the caller can tell whether the buffer
+// was too short, started with a partial BOM, or had no BOM at all.
+func DetectWithDiagnostics(buffer []byte) (BOMType, Reason) {
+	detection := Detect(buffer)
+
+	switch {
+	case detection.Type != Unknown:
+		return detection.Type, Found
+	case len(buffer) > 0 && detection.TooShort:
+		return Unknown, PartialBOM
+	case len(buffer) < shortestSignatureLen():
+		return Unknown, TooShort
+	}
+
+	return Unknown, NoMatch
+}
+
+// shortestSignatureLen returns the size of the shortest known BOM
+func shortestSignatureLen() int {
+	return len(signatures[len(signatures)-1].bytes)
+}
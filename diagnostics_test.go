@@ -0,0 +1,29 @@
+package gobom
+
+import "testing"
+
+func TestDetectWithDiagnostics(t *testing.T) {
+	tests := []struct {
+		buffer     []byte
+		want       BOMType
+		wantReason Reason
+	}{
+		{nil, Unknown, TooShort},
+		{[]byte("a"), Unknown, TooShort},
+		{[]byte{0xEF}, Unknown, PartialBOM},
+		{[]byte{0xEF, 0xBB}, Unknown, PartialBOM},
+		{[]byte{0x00, 0x00, 0xFE}, Unknown, PartialBOM},
+		{[]byte("hello"), Unknown, NoMatch},
+		{[]byte{0xEF, 0xBB, 'a'}, Unknown, NoMatch},
+		{[]byte{0xEF, 0xBB, 0xBF}, UTF8, Found},
+		{[]byte{0xFF, 0xFE}, UTF16LE, Found},
+	}
+
+	for _, test := range tests {
+		got, reason := DetectWithDiagnostics(test.buffer)
+		if got != test.want || reason != test.wantReason {
+			t.Errorf("DetectWithDiagnostics(%v) = %s, %q, want %s, %q",
+				test.buffer, got, reason, test.want, test.wantReason)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package gobom
+
+import (
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// Encoding returns the x/text encoding of t, so a decoder can be created
+// directly from the detection result. The decoders of the returned encodings
+// remove the BOM, and their encoders write it, so t.Encoding().NewDecoder()
+// can be used on the content as is, BOM and all.
+//
+// Only UTF-8, UTF-16 and UTF-32 are supported, and nil is returned for any
+// other BOM type, including Unknown.
+func (t BOMType) Encoding() encoding.Encoding {
+	switch t {
+	case UTF8:
+		return unicode.UTF8BOM
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+	case UTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.UseBOM)
+	case UTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.UseBOM)
+	}
+
+	return nil
+}
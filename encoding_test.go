@@ -0,0 +1,43 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBOMTypeEncoding(t *testing.T) {
+	tests := []struct {
+		bomType BOMType
+		encoded []byte
+	}{
+		{UTF8, []byte("\xEF\xBB\xBFh€")},
+		{UTF16LE, []byte{0xFF, 0xFE, 'h', 0x00, 0xAC, 0x20}},
+		{UTF16BE, []byte{0xFE, 0xFF, 0x00, 'h', 0x20, 0xAC}},
+		{UTF32LE, []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00, 0xAC, 0x20, 0x00, 0x00}},
+		{UTF32BE, []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'h', 0x00, 0x00, 0x20, 0xAC}},
+	}
+
+	for _, test := range tests {
+		enc := test.bomType.Encoding()
+		if enc == nil {
+			t.Errorf("%s.Encoding() = nil", test.bomType)
+			continue
+		}
+
+		decoded, err := enc.NewDecoder().Bytes(test.encoded)
+		if err != nil || string(decoded) != "h€" {
+			t.Errorf("%s: decoded %q, %v, want %q", test.bomType, decoded, err, "h€")
+		}
+
+		encoded, err := enc.NewEncoder().String("h€")
+		if err != nil || !bytes.Equal([]byte(encoded), test.encoded) {
+			t.Errorf("%s: encoded %v, %v, want %v", test.bomType, []byte(encoded), err, test.encoded)
+		}
+	}
+
+	for _, bomType := range []BOMType{Unknown, GB18030, UTF7, SCSU} {
+		if enc := bomType.Encoding(); enc != nil {
+			t.Errorf("%s.Encoding() = %v, want nil", bomType, enc)
+		}
+	}
+}
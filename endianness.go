@@ -0,0 +1,53 @@
+package gobom
+
+import "encoding/binary"
+
+// Endianness holds the byte order of an encoding
+type Endianness uint8
+
+// Enumeration of the byte orders an encoding can have
+const (
+	// NotApplicable is for encodings without byte order, such as UTF-8
+	NotApplicable Endianness = iota
+	LittleEndian
+	BigEndian
+)
+
+// String is an implementation of fmt.Stringer interface
+func (e Endianness) String() string {
+	switch e {
+	case LittleEndian:
+		return "LittleEndian"
+	case BigEndian:
+		return "BigEndian"
+	}
+
+	return "NotApplicable"
+}
+
+// Endianness returns the byte order of t. UTF-8 and Unknown have no byte
+// order, and return NotApplicable.
+func (t BOMType) Endianness() Endianness {
+	switch t {
+	case UTF16LE, UTF32LE:
+		return LittleEndian
+	case UTF16BE, UTF32BE:
+		return BigEndian
+	}
+
+	return NotApplicable
+}
+
+// ByteOrder returns the binary.ByteOrder of t, for decoding UTF-16 and UTF-32
+// content manually.
+// If t has no byte order (UTF-8 and Unknown), it returns false.
+func (t BOMType) ByteOrder() (binary.ByteOrder, bool) {
+	switch t.Endianness() {
+	case LittleEndian:
+		return binary.LittleEndian, true
+	case BigEndian:
+		return binary.BigEndian, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,49 @@
+package gobom
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestBOMTypeEndianness(t *testing.T) {
+	tests := []struct {
+		bomType BOMType
+		want    Endianness
+	}{
+		{Unknown, NotApplicable},
+		{UTF8, NotApplicable},
+		{UTF16LE, LittleEndian},
+		{UTF16BE, BigEndian},
+		{UTF32LE, LittleEndian},
+		{UTF32BE, BigEndian},
+		{GB18030, NotApplicable},
+	}
+
+	for _, test := range tests {
+		if got := test.bomType.Endianness(); got != test.want {
+			t.Errorf("%s.Endianness() = %s, want %s", test.bomType, got, test.want)
+		}
+	}
+}
+
+func TestBOMTypeByteOrder(t *testing.T) {
+	tests := []struct {
+		bomType BOMType
+		want    binary.ByteOrder
+		ok      bool
+	}{
+		{Unknown, nil, false},
+		{UTF8, nil, false},
+		{UTF16LE, binary.LittleEndian, true},
+		{UTF16BE, binary.BigEndian, true},
+		{UTF32LE, binary.LittleEndian, true},
+		{UTF32BE, binary.BigEndian, true},
+	}
+
+	for _, test := range tests {
+		got, ok := test.bomType.ByteOrder()
+		if got != test.want || ok != test.ok {
+			t.Errorf("%s.ByteOrder() = %v, %t, want %v, %t", test.bomType, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package gobom
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that are returned by the detection functions
+var (
+	// ErrShortBuffer is returned when a buffer is too small to detect a BOM
+	ErrShortBuffer = errors.New("gobom: buffer is too small to detect BOM")
+	// ErrNoBOM is returned when a buffer does not start with a BOM
+	ErrNoBOM = errors.New("gobom: no BOM was found")
+	// ErrNotDoubleEncoded is returned when reversing a double encoding of a
+	// content that was not double encoded
+	ErrNotDoubleEncoded = errors.New("gobom: content is not double encoded")
+	// ErrNotSeeker is returned when seeking a Reader that wraps a reader that
+	// does not implement io.Seeker
+	ErrNotSeeker = errors.New("gobom: reader does not implement io.Seeker")
+	// ErrNegativePosition is returned when seeking to a position before the
+	// beginning of the content
+	ErrNegativePosition = errors.New("gobom: negative position")
+	// ErrUnsupportedEncoding is returned when decoding or encoding content of
+	// a BOM type other than UTF-8, UTF-16 and UTF-32
+	ErrUnsupportedEncoding = errors.New("gobom: encoding of BOM type is not supported")
+	// ErrBOMDecided is returned when setting the BOM of a DeferredWriter after
+	// it was already decided
+	ErrBOMDecided = errors.New("gobom: BOM was already decided")
+)
+
+// UnexpectedBOMError is returned by a Reader that was created with WithExpect,
+// when its content starts with a BOM type that was not expected
+type UnexpectedBOMError struct {
+	// Type is the BOM type that was found
+	Type BOMType
+	// Expected are the BOM types that were expected
+	Expected []BOMType
+}
+
+// Error is an implementation of error interface
+func (e *UnexpectedBOMError) Error() string {
+	return fmt.Sprintf("gobom: unexpected BOM type %s, expected one of %v", e.Type, e.Expected)
+}
+
+// InvalidUTF8Error is returned by a Reader that was created with
+// WithValidateUTF8, when its content is not valid UTF-8
+type InvalidUTF8Error struct {
+	// Offset is the offset of the invalid sequence at the content the Reader
+	// returns
+	Offset int64
+}
+
+// Error is an implementation of error interface
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("gobom: invalid UTF-8 at offset %d", e.Offset)
+}
+
+// ExistingBOMError is returned by a Writer that was created with
+// WithRejectExistingBOM, when the content already starts with a BOM
+type ExistingBOMError struct {
+	// Type is the BOM type the content starts with
+	Type BOMType
+}
+
+// Error is an implementation of error interface
+func (e *ExistingBOMError) Error() string {
+	return fmt.Sprintf("gobom: content already starts with a BOM of type %s", e.Type)
+}
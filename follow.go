@@ -0,0 +1,97 @@
+package gobom
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// defaultPollInterval is the time FollowBOM waits between reads, when it is
+// not given a notifier
+const defaultPollInterval = 100 * time.Millisecond
+
+// followOptions holds the configuration of FollowBOM
+type followOptions struct {
+	interval time.Duration
+	notify   <-chan struct{}
+}
+
+// FollowOption configures FollowBOM
+type FollowOption func(*followOptions)
+
+// WithPollInterval makes FollowBOM read the file again every d, instead of
+// every 100 milliseconds. A non-positive d keeps the default.
+func WithPollInterval(d time.Duration) FollowOption {
+	return func(o *followOptions) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithNotifier makes FollowBOM read the file again when a value is received
+// from notify, such as on a file system write event, instead of polling.
+// Closing notify tells FollowBOM that the file will not grow anymore, and the
+// detection is made on the bytes it holds.
+func WithNotifier(notify <-chan struct{}) FollowOption {
+	return func(o *followOptions) {
+		o.notify = notify
+	}
+}
+
+// FollowBOM detects the BOM type of a file that is still being written, such
+// as a log file that was just created. It reads the beginning of r again,
+// every time it is polled or notified, until the bytes that were written
+// decide the BOM, and it returns the BOM type.
+//
+// When ctx is done before the BOM is decided, Unknown and the error of ctx are
+// returned. Any error of r other than io.EOF is returned as is, with Unknown
+// as the BOM type.
+func FollowBOM(ctx context.Context, r io.ReaderAt, opts ...FollowOption) (BOMType, error) {
+	options := followOptions{interval: defaultPollInterval}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	buffer := make([]byte, maxBOMLen)
+	for {
+		n, err := r.ReadAt(buffer, 0)
+		if err != nil && err != io.EOF {
+			return Unknown, err
+		}
+		if bomDecided(buffer[:n]) {
+			return DetectBOMTypeFromBuffer(buffer[:n]), nil
+		}
+
+		if options.notify != nil {
+			select {
+			case <-ctx.Done():
+				return Unknown, ctx.Err()
+			case _, ok := <-options.notify:
+				if !ok {
+					return detectFinal(r, buffer)
+				}
+			}
+			continue
+		}
+
+		timer := time.NewTimer(options.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Unknown, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// detectFinal detects the BOM type out of the bytes r holds, once it is known
+// that r does not grow anymore
+func detectFinal(r io.ReaderAt, buffer []byte) (BOMType, error) {
+	n, err := r.ReadAt(buffer, 0)
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+
+	return DetectBOMTypeFromBuffer(buffer[:n]), nil
+}
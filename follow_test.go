@@ -0,0 +1,95 @@
+package gobom
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// growingFile is an io.ReaderAt whose content is appended while it is read
+type growingFile struct {
+	mutex   sync.Mutex
+	content []byte
+}
+
+func (g *growingFile) ReadAt(p []byte, off int64) (int, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if off >= int64(len(g.content)) {
+		return 0, io.EOF
+	}
+	n := copy(p, g.content[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (g *growingFile) append(b ...byte) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.content = append(g.content, b...)
+}
+
+func TestFollowBOMPolling(t *testing.T) {
+	file := &growingFile{}
+	go func() {
+		for _, b := range []byte{0xFF, 0xFE, 0x00, 0x00} {
+			time.Sleep(5 * time.Millisecond)
+			file.append(b)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := FollowBOM(ctx, file, WithPollInterval(time.Millisecond))
+	if got != UTF32LE || err != nil {
+		t.Errorf("FollowBOM() = %s, %v, want %s", got, err, UTF32LE)
+	}
+}
+
+func TestFollowBOMNotifier(t *testing.T) {
+	file := &growingFile{}
+	// FollowBOM may see the whole BOM on the first notification, and return
+	// before it receives the second one
+	notify := make(chan struct{}, 2)
+	result := make(chan BOMType, 1)
+
+	go func() {
+		bomType, _ := FollowBOM(context.Background(), file, WithNotifier(notify))
+		result <- bomType
+	}()
+
+	file.append(0xEF, 0xBB)
+	notify <- struct{}{}
+	file.append(0xBF)
+	notify <- struct{}{}
+
+	if got := <-result; got != UTF8 {
+		t.Errorf("FollowBOM() = %s, want %s", got, UTF8)
+	}
+
+	// a closed notifier decides on what was written
+	file = &growingFile{content: []byte{0xFF, 0xFE}}
+	closed := make(chan struct{})
+	close(closed)
+	got, err := FollowBOM(context.Background(), file, WithNotifier(closed))
+	if got != UTF16LE || err != nil {
+		t.Errorf("FollowBOM() = %s, %v, want %s", got, err, UTF16LE)
+	}
+}
+
+func TestFollowBOMCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	got, err := FollowBOM(ctx, &growingFile{}, WithPollInterval(time.Millisecond))
+	if got != Unknown || err != context.DeadlineExceeded {
+		t.Errorf("FollowBOM() = %s, %v, want %s, %v", got, err, Unknown, context.DeadlineExceeded)
+	}
+}
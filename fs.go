@@ -0,0 +1,242 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+
+	"golang.org/x/text/transform"
+)
+
+// DetectFS opens the file name of fsys, reads at most the size of the longest
+// BOM from its beginning, detects the BOM type out of them, and closes it, in
+// the same manner as DetectFile, for file systems such as embed.FS,
+// fstest.MapFS and zip.Reader.
+func DetectFS(fsys fs.FS, name string) (BOMType, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return Unknown, err
+	}
+	defer file.Close()
+
+	return DetectBOMTypeFromReader(file)
+}
+
+// stripFS is a file system whose files are read without their BOM
+type stripFS struct {
+	fsys   fs.FS
+	decode bool
+}
+
+// FSOption configures the file system NewStripFS returns
+type FSOption func(*stripFS)
+
+// WithDecodeToUTF8 makes the file system decode UTF-16, UTF-32 and GB18030
+// files to UTF-8, as NewUTF8Reader does. Since the size of the decoded content is known
+// only after it was decoded, such files are decoded into memory when they are
+// opened.
+func WithDecodeToUTF8() FSOption {
+	return func(s *stripFS) {
+		s.decode = true
+	}
+}
+
+// NewStripFS returns a file system that opens the files of fsys without their
+// BOM, so template engines and file servers that are built on fs.FS never see
+// it, and configures it with opts.
+//
+// The size that Stat and the entries of ReadDir report for a file is the size
+// of its content without the BOM. Files that fsys opens as io.Seeker can be
+// seeked, as with Reader, so http.FS serves them correctly.
+func NewStripFS(fsys fs.FS, opts ...FSOption) fs.FS {
+	s := &stripFS{fsys: fsys}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Open is an implementation of fs.FS interface
+func (s *stripFS) Open(name string) (fs.File, error) {
+	file, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		return &strippedDir{File: file, fsys: s, name: name}, nil
+	}
+
+	stripped := &strippedFile{File: file, reader: NewReader(file)}
+	if !s.decode {
+		return stripped, nil
+	}
+
+	// an error of detection is returned by Read
+	bomType, _ := stripped.reader.DetectNow()
+	decoder := decoderOf(bomType)
+	if decoder == nil {
+		return stripped, nil
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(transform.NewReader(stripped.reader, decoder))
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return &decodedFile{
+		Reader: bytes.NewReader(content),
+		info:   sizedInfo{FileInfo: info, size: int64(len(content))},
+	}, nil
+}
+
+// ReadDir is an implementation of fs.ReadDirFS interface
+func (s *stripFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(s.fsys, name)
+	return s.wrapEntries(name, entries), err
+}
+
+// wrapEntries makes the entries of the directory dir report the size of their
+// content without the BOM
+func (s *stripFS) wrapEntries(dir string, entries []fs.DirEntry) []fs.DirEntry {
+	for i, entry := range entries {
+		if !entry.IsDir() {
+			entries[i] = strippedEntry{DirEntry: entry, fsys: s, name: path.Join(dir, entry.Name())}
+		}
+	}
+
+	return entries
+}
+
+// strippedEntry is a directory entry of a file of stripFS
+type strippedEntry struct {
+	fs.DirEntry
+	fsys *stripFS
+	name string
+}
+
+// Info is an implementation of fs.DirEntry interface. The file is opened in
+// order to find the size of its content.
+func (s strippedEntry) Info() (fs.FileInfo, error) {
+	return fs.Stat(s.fsys, s.name)
+}
+
+// strippedDir is a directory of stripFS
+type strippedDir struct {
+	fs.File
+	fsys *stripFS
+	name string
+}
+
+// ReadDir is an implementation of fs.ReadDirFile interface
+func (s *strippedDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	dir, ok := s.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: s.name, Err: errors.New("not implemented")}
+	}
+
+	entries, err := dir.ReadDir(n)
+	return s.fsys.wrapEntries(s.name, entries), err
+}
+
+// sizedInfo is a fs.FileInfo with another size
+type sizedInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+// Size is an implementation of fs.FileInfo interface
+func (s sizedInfo) Size() int64 {
+	return s.size
+}
+
+// strippedFile is a file that is read without its BOM
+type strippedFile struct {
+	fs.File
+	reader *Reader
+}
+
+// Read is an implementation of io.Reader interface
+func (s *strippedFile) Read(buffer []byte) (int, error) {
+	return s.reader.Read(buffer)
+}
+
+// Seek is an implementation of io.Seeker interface, in the same manner as
+// Reader.Seek
+func (s *strippedFile) Seek(offset int64, whence int) (int64, error) {
+	return s.reader.Seek(offset, whence)
+}
+
+// Stat is an implementation of fs.File interface, that reports the size of
+// the content without the BOM
+func (s *strippedFile) Stat() (fs.FileInfo, error) {
+	info, err := s.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.reader.DetectNow(); err != nil {
+		return nil, err
+	}
+
+	return sizedInfo{FileInfo: info, size: info.Size() - int64(s.reader.skip)}, nil
+}
+
+// decodedFile is a file whose content was decoded into memory
+type decodedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+// Stat is an implementation of fs.File interface
+func (d *decodedFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Close is an implementation of fs.File interface
+func (d *decodedFile) Close() error {
+	return nil
+}
+
+// FileBOM is a file that starts with a BOM
+type FileBOM struct {
+	// Path is the path of the file at the file system
+	Path string
+	// Type is the BOM type the file starts with
+	Type BOMType
+}
+
+// FindBOMFiles walks the tree of fsys at root, and returns every file that
+// starts with a BOM, in lexical order, such as for a test or a startup check
+// that asserts that no BOM prefixed assets were compiled into an embed.FS.
+//
+// An empty slice is returned when no file starts with a BOM. The walk stops at
+// the first error, which is returned with the files that were found until
+// then.
+func FindBOMFiles(fsys fs.FS, root string) ([]FileBOM, error) {
+	files := []FileBOM{}
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		bomType, err := DetectFS(fsys, name)
+		if err != nil {
+			return err
+		}
+		if bomType != Unknown {
+			files = append(files, FileBOM{Path: name, Type: bomType})
+		}
+		return nil
+	})
+
+	return files, err
+}
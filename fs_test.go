@@ -0,0 +1,154 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"utf8.txt":       {Data: []byte("\xEF\xBB\xBFhello")},
+		"dir/utf16le.md": {Data: []byte{0xFF, 0xFE, 'h', 0}},
+		"plain.txt":      {Data: []byte("hello")},
+		"empty.txt":      {Data: nil},
+	}
+
+	tests := []struct {
+		name string
+		want BOMType
+	}{
+		{"utf8.txt", UTF8},
+		{"dir/utf16le.md", UTF16LE},
+		{"plain.txt", Unknown},
+		{"empty.txt", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectFS(fsys, tt.name)
+			if got != tt.want || err != nil {
+				t.Errorf("DetectFS() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+
+	if got, err := DetectFS(fsys, "missing.txt"); got != Unknown || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("DetectFS() = %s, %v, want %s, %v", got, err, Unknown, fs.ErrNotExist)
+	}
+}
+
+func TestNewStripFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"utf8.txt":        {Data: []byte("\xEF\xBB\xBFcafé")},
+		"plain.txt":       {Data: []byte("café")},
+		"dir/utf16le.txt": {Data: mustEncode(t, "café", UTF16LE)},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []FSOption
+		file   string
+		want   []byte
+		seeker bool
+	}{
+		{"utf8", nil, "utf8.txt", []byte("café"), true},
+		{"plain", nil, "plain.txt", []byte("café"), true},
+		{"utf16le", nil, "dir/utf16le.txt", mustEncode(t, "café", UTF16LE)[2:], true},
+		{"decoded utf8", []FSOption{WithDecodeToUTF8()}, "utf8.txt", []byte("café"), true},
+		{"decoded utf16le", []FSOption{WithDecodeToUTF8()}, "dir/utf16le.txt", []byte("café"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped := NewStripFS(fsys, tt.opts...)
+
+			got, err := fs.ReadFile(stripped, tt.file)
+			if err != nil || !bytes.Equal(got, tt.want) {
+				t.Errorf("ReadFile() = %q, %v, want %q", got, err, tt.want)
+			}
+
+			info, err := fs.Stat(stripped, tt.file)
+			if err != nil || info.Size() != int64(len(tt.want)) {
+				t.Errorf("Stat() size = %v, %v, want %d", info, err, len(tt.want))
+			}
+
+			file, err := stripped.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			seeker, ok := file.(io.Seeker)
+			if !ok {
+				t.Fatal("file does not implement io.Seeker")
+			}
+			if end, err := seeker.Seek(0, io.SeekEnd); err != nil || end != int64(len(tt.want)) {
+				t.Errorf("Seek(0, io.SeekEnd) = %d, %v, want %d", end, err, len(tt.want))
+			}
+			seeker.Seek(0, io.SeekStart)
+			if got, err := io.ReadAll(file); err != nil || !bytes.Equal(got, tt.want) {
+				t.Errorf("ReadAll() after Seek = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStripFSWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     {Data: []byte("\xEF\xBB\xBFa")},
+		"dir/b.txt": {Data: []byte("b")},
+		"dir/c.txt": {Data: mustEncode(t, "c", UTF16BE)},
+	}
+
+	for _, opts := range [][]FSOption{nil, {WithDecodeToUTF8()}} {
+		if err := fstest.TestFS(NewStripFS(fsys, opts...), "a.txt", "dir/b.txt"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := NewStripFS(fsys).Open("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestFindBOMFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":          {Data: []byte("\xEF\xBB\xBF<html>")},
+		"static/app.js":       {Data: []byte("let a")},
+		"static/style.css":    {Data: []byte{0xFF, 0xFE, 'a', 0}},
+		"templates/empty.tpl": {Data: nil},
+		"templates/page.tpl":  {Data: []byte("\xEF\xBB\xBF{{.}}")},
+	}
+
+	got, err := FindBOMFiles(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FileBOM{
+		{"index.html", UTF8},
+		{"static/style.css", UTF16LE},
+		{"templates/page.tpl", UTF8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindBOMFiles() = %v, want %v", got, want)
+	}
+
+	got, err = FindBOMFiles(fsys, "static")
+	if err != nil || !reflect.DeepEqual(got, want[1:2]) {
+		t.Errorf("FindBOMFiles(static) = %v, %v, want %v", got, err, want[1:2])
+	}
+
+	got, err = FindBOMFiles(fstest.MapFS{"a.txt": {Data: []byte("a")}}, ".")
+	if err != nil || len(got) != 0 || got == nil {
+		t.Errorf("FindBOMFiles() = %#v, %v, want an empty slice", got, err)
+	}
+
+	if _, err := FindBOMFiles(fsys, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindBOMFiles() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
@@ -23,21 +23,39 @@ If a buffer is too small to detect BOM type it also returns "Unknown"
 */
 package gobom
 
-import (
-	"bytes"
-	"io"
-)
+import "bytes"
 
 // BOM Headers to detect
 // The information is from: http://www.unicode.org/faq/utf_bom.html#BOM
 var (
-	UTF8Bom    = []byte{0xEF, 0xBB, 0xBF}
-	UTF16LEBom = []byte{0xFF, 0xFE}
-	UTF16BEBom = []byte{0xFE, 0xFF}
-	UTF32LEBom = []byte{0xFF, 0xFE, 0x00, 0x00}
-	UTF32BEBom = []byte{0x00, 0x00, 0xFE, 0xFF}
+	UTF8Bom      = []byte{0xEF, 0xBB, 0xBF}
+	UTF16LEBom   = []byte{0xFF, 0xFE}
+	UTF16BEBom   = []byte{0xFE, 0xFF}
+	UTF32LEBom   = []byte{0xFF, 0xFE, 0x00, 0x00}
+	UTF32BEBom   = []byte{0x00, 0x00, 0xFE, 0xFF}
+	GB18030Bom   = []byte{0x84, 0x31, 0x95, 0x33}
+	UTF7Bom      = []byte{0x2B, 0x2F, 0x76, 0x38, 0x2D}
+	UTFEBCDICBom = []byte{0xDD, 0x73, 0x66, 0x73}
+	UTF1Bom      = []byte{0xF7, 0x64, 0x4C}
+	SCSUBom      = []byte{0x0E, 0xFE, 0xFF}
+	BOCU1Bom     = []byte{0xFB, 0xEE, 0x28}
 )
 
+// UTF7Bom is the form of UTF-7 BOM that closes the base64 block ("+/v8-").
+// UTF7BomVariants holds the other forms of UTF-7 BOM. The last byte of each of
+// them holds also the first bits of the next char, so removing them does not
+// leave a valid UTF-7 content: they are detected, but never removed.
+var UTF7BomVariants = [][]byte{
+	{0x2B, 0x2F, 0x76, 0x38},
+	{0x2B, 0x2F, 0x76, 0x39},
+	{0x2B, 0x2F, 0x76, 0x2B},
+	{0x2B, 0x2F, 0x76, 0x2F},
+}
+
+// BOMRune is the BOM as a single rune (ZERO WIDTH NO-BREAK SPACE). This is what
+// a BOM turns into after the content was decoded.
+const BOMRune = '\uFEFF'
+
 // BOMType holds the type of BOM that was detected
 type BOMType uint8
 
@@ -49,42 +67,175 @@ const (
 	UTF16BE
 	UTF32LE
 	UTF32BE
+	GB18030
+	UTF7
+	UTF1
+	UTFEBCDIC
+	SCSU
+	BOCU1
 )
 
-// Reader is an implementation for the io.Reader
-type Reader struct {
-	reader io.Reader
-	buffer []byte
-	err    error
+// signature ties a BOM type to the bytes that represent it
+type signature struct {
+	bomType BOMType
+	bytes   []byte
+}
+
+// signatures holds the known BOMs, ordered from the longest to the shortest
+// one, so the first match is also the longest one.
+//
+// It is important, because UTF32LE BOM starts with the UTF16LE BOM.
+// A BOM type can have more than one signature, and the first one is the one
+// that is used for writing the BOM.
+var signatures = []signature{
+	{UTF7, UTF7Bom},
+	{UTF32LE, UTF32LEBom},
+	{UTF32BE, UTF32BEBom},
+	{GB18030, GB18030Bom},
+	{UTFEBCDIC, UTFEBCDICBom},
+	{UTF7, UTF7BomVariants[0]},
+	{UTF7, UTF7BomVariants[1]},
+	{UTF7, UTF7BomVariants[2]},
+	{UTF7, UTF7BomVariants[3]},
+	{UTF8, UTF8Bom},
+	{UTF1, UTF1Bom},
+	{SCSU, SCSUBom},
+	{BOCU1, BOCU1Bom},
+	{UTF16LE, UTF16LEBom},
+	{UTF16BE, UTF16BEBom},
+}
+
+// detectSignature returns the BOM type of the longest signature that buffer
+// starts with
+func detectSignature(buffer []byte) BOMType {
+	return DetectFrom(buffer)
+}
+
+// DetectFrom detects the BOM type of data, that can be any string or byte
+// slice type, including named types such as json.RawMessage, without
+// converting it first.
+// When more than one BOM matches, the longest one is returned.
+func DetectFrom[T ~string | ~[]byte](data T) BOMType {
+	return matchSignature(signatures, data)
+}
+
+// matchSignature returns the BOM type of the first signature in sigs that data
+// starts with
+func matchSignature[T ~string | ~[]byte](sigs []signature, data T) BOMType {
+	bomType, _ := matchSignatureLen(sigs, data)
+	return bomType
+}
+
+// matchSignatureLen returns the BOM type of the first signature in sigs that
+// data starts with, and the number of bytes of data that can be removed with
+// it, which is 0 for a signature that cannot be removed (see removable).
+func matchSignatureLen[T ~string | ~[]byte](sigs []signature, data T) (BOMType, int) {
+	for _, sig := range sigs {
+		if hasPrefix(data, sig.bytes) {
+			if !sig.removable() {
+				return sig.bomType, 0
+			}
+			return sig.bomType, len(sig.bytes)
+		}
+	}
+
+	return Unknown, 0
+}
+
+// removable checks if the bytes of s can be removed from the content. The
+// forms of UTF-7 BOM other than UTF7Bom cannot, as their last byte holds bits
+// of the next char, so they are detected, but left in the content.
+func (s signature) removable() bool {
+	return s.bomType != UTF7 || bytes.Equal(s.bytes, UTF7Bom)
+}
+
+// DetectAllCandidates returns every BOM type that buffer starts with, ordered
+// from the longest signature to the shortest one.
+//
+// A buffer such as FF FE 00 00 is both UTF32LE BOM and UTF16LE BOM followed by
+// a NUL char, so the caller can decide which one to prefer.
+// If no BOM is found, it returns an empty slice.
+func DetectAllCandidates(buffer []byte) []BOMType {
+	return matchCandidates(signatures, buffer)
+}
+
+// matchCandidates returns the BOM type of every signature in sigs that buffer
+// starts with, in the order of sigs
+func matchCandidates(sigs []signature, buffer []byte) []BOMType {
+	candidates := []BOMType{}
+	for _, sig := range sigs {
+		if !bytes.HasPrefix(buffer, sig.bytes) {
+			continue
+		}
+
+		// a BOM type with more than one signature is reported only once
+		found := false
+		for _, candidate := range candidates {
+			if candidate == sig.bomType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			candidates = append(candidates, sig.bomType)
+		}
+	}
+
+	return candidates
 }
 
-// DetectBOMTypeFromBytes try to detect the type of BOM provided by a buffer in
-// a naive manner. It means that the detection is very simple but a bit costly
-// regarding the way it detects.
+// DetectBOMTypeFromBytes try to detect the type of BOM provided by a buffer.
+// When more than one BOM matches, the longest one is returned.
 //
-// The buffer must at least have 5 bytes, so from 2 - 4 bytes will be the BOM
+// The buffer must at least have 5 bytes, so from 2 - 5 bytes will be the BOM
 // if they do not exists, it returns Unknown
+//
+// Use DetectBOMTypeFromBytesWithMode and ExactLengthMode in order to detect
+// buffers that are shorter than that.
 func DetectBOMTypeFromBytes(buffer []byte) BOMType {
-	if len(buffer) < 5 {
+	return DetectBOMTypeFromBytesWithMode(buffer, MinLengthMode)
+}
+
+// DetectionMode controls how short a buffer can be in order to detect BOM
+type DetectionMode uint8
+
+// Enumeration of the detection modes
+const (
+	// MinLengthMode requires the buffer to have at least 5 bytes, and is the
+	// mode DetectBOMTypeFromBytes uses
+	MinLengthMode DetectionMode = iota
+	// ExactLengthMode detects a BOM when the buffer is at least as long as the
+	// BOM itself, so a buffer holding only a BOM is detected as well
+	ExactLengthMode
+)
+
+// minLegacyLength is the minimum size of a buffer in MinLengthMode
+const minLegacyLength = 5
+
+// DetectBOMTypeFromBytesWithMode detects the BOM type of buffer based on mode.
+// When more than one BOM matches, the longest one is returned.
+func DetectBOMTypeFromBytesWithMode(buffer []byte, mode DetectionMode) BOMType {
+	if mode == MinLengthMode && len(buffer) < minLegacyLength {
 		return Unknown
 	}
 
-	// Naive checking for BOM based on size of BOM to validate.
-	// it's a bit slow
+	return detectSignature(buffer)
+}
 
-	if bytes.HasPrefix(buffer, UTF16LEBom) {
-		return UTF16LE
-	} else if bytes.HasPrefix(buffer, UTF16BEBom) {
-		return UTF16BE
-	} else if bytes.HasPrefix(buffer, UTF8Bom) {
-		return UTF8
-	} else if bytes.HasPrefix(buffer, UTF32LEBom) {
-		return UTF32LE
-	} else if bytes.HasPrefix(buffer, UTF32BEBom) {
-		return UTF32BE
+// DetectBOMTypeFromBytesE is the same as DetectBOMTypeFromBytes, but returns
+// ErrShortBuffer when the buffer has less than 5 bytes, and ErrNoBOM when no
+// BOM was found, so the two cases can be told apart using errors.Is.
+func DetectBOMTypeFromBytesE(buffer []byte) (BOMType, error) {
+	if len(buffer) < minLegacyLength {
+		return Unknown, ErrShortBuffer
 	}
 
-	return Unknown
+	bomType := detectSignature(buffer)
+	if bomType == Unknown {
+		return Unknown, ErrNoBOM
+	}
+
+	return bomType, nil
 }
 
 // IsUTF8BOM validate a buffer if it has UTF8 BOM, if buffer is too small it
@@ -96,7 +247,7 @@ func IsUTF8BOM(buffer []byte) bool {
 
 	return buffer[0] == UTF8Bom[0] &&
 		buffer[1] == UTF8Bom[1] &&
-		buffer[3] == UTF8Bom[2]
+		buffer[2] == UTF8Bom[2]
 }
 
 // IsUTF16LEBOM validate a buffer if it has UTF16 Little Endian.
@@ -155,55 +306,147 @@ func IsUTF32BOM(buffer []byte) bool {
 	return IsUTF32LEBOM(buffer) || IsUTF32BEBOM(buffer)
 }
 
-//DetectBOMTypeFromBuffer detects the BOM type using the "IsUTFXXXXXBOM"
-func DetectBOMTypeFromBuffer(buffer []byte) BOMType {
-	if IsUTF8BOM(buffer) {
-		return UTF8
-	} else if IsUTF16LEBOM(buffer) {
-		return UTF16LE
-	} else if IsUTF16BEBOM(buffer) {
-		return UTF16BE
-	} else if IsUTF32LEBOM(buffer) {
-		return UTF32LE
-	} else if IsUTF32BEBOM(buffer) {
-		return UTF32BE
+// IsGB18030BOM detects if a buffer contains GB18030 BOM.
+// If the buffer is too small, it returns false.
+func IsGB18030BOM(buffer []byte) bool {
+	if len(buffer) < len(GB18030Bom) {
+		return false
 	}
-	return Unknown
+
+	return buffer[0] == GB18030Bom[0] &&
+		buffer[1] == GB18030Bom[1] &&
+		buffer[2] == GB18030Bom[2] &&
+		buffer[3] == GB18030Bom[3]
+}
+
+// IsUTF7BOM detects if a buffer contains any of the forms of UTF-7 BOM.
+// If the buffer is too small, it returns false.
+func IsUTF7BOM(buffer []byte) bool {
+	if bytes.HasPrefix(buffer, UTF7Bom) {
+		return true
+	}
+
+	for _, variant := range UTF7BomVariants {
+		if bytes.HasPrefix(buffer, variant) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsUTF1BOM detects if a buffer contains UTF-1 BOM.
+// If the buffer is too small, it returns false.
+func IsUTF1BOM(buffer []byte) bool {
+	if len(buffer) < len(UTF1Bom) {
+		return false
+	}
+
+	return buffer[0] == UTF1Bom[0] &&
+		buffer[1] == UTF1Bom[1] &&
+		buffer[2] == UTF1Bom[2]
+}
+
+// IsUTFEBCDICBOM detects if a buffer contains UTF-EBCDIC BOM.
+// If the buffer is too small, it returns false.
+func IsUTFEBCDICBOM(buffer []byte) bool {
+	if len(buffer) < len(UTFEBCDICBom) {
+		return false
+	}
+
+	return buffer[0] == UTFEBCDICBom[0] &&
+		buffer[1] == UTFEBCDICBom[1] &&
+		buffer[2] == UTFEBCDICBom[2] &&
+		buffer[3] == UTFEBCDICBom[3]
+}
+
+// IsSCSUBOM detects if a buffer contains SCSU BOM.
+// If the buffer is too small, it returns false.
+func IsSCSUBOM(buffer []byte) bool {
+	if len(buffer) < len(SCSUBom) {
+		return false
+	}
+
+	return buffer[0] == SCSUBom[0] &&
+		buffer[1] == SCSUBom[1] &&
+		buffer[2] == SCSUBom[2]
+}
+
+// IsBOCU1BOM detects if a buffer contains BOCU-1 BOM.
+// If the buffer is too small, it returns false.
+func IsBOCU1BOM(buffer []byte) bool {
+	if len(buffer) < len(BOCU1Bom) {
+		return false
+	}
+
+	return buffer[0] == BOCU1Bom[0] &&
+		buffer[1] == BOCU1Bom[1] &&
+		buffer[2] == BOCU1Bom[2]
+}
+
+// DetectBOMTypeFromBuffer detects the BOM type of a buffer of any size.
+// When more than one BOM matches, the longest one is returned.
+func DetectBOMTypeFromBuffer(buffer []byte) BOMType {
+	return detectSignature(buffer)
+}
+
+// HasBOM returns true if buffer starts with any of the supported BOMs.
+func HasBOM(buffer []byte) bool {
+	return DetectBOMTypeFromBuffer(buffer) != Unknown
 }
 
 // BytesToSkip returns the number of bytes to skip in order to "ignore" BOM, or
-// -1 if non found
+// -1 if non found.
+//
+// A UTF-7 BOM other than UTF7Bom cannot be skipped without corrupting the
+// char that follows it, so 0 is returned for it.
 func BytesToSkip(buffer []byte) int {
-	BomType := map[BOMType]int{
-		UTF8:    len(UTF8Bom),
-		UTF16LE: len(UTF16LEBom),
-		UTF16BE: len(UTF16BEBom),
-		UTF32LE: len(UTF32LEBom),
-		UTF32BE: len(UTF32BEBom),
-		Unknown: -1,
+	bomType, size := matchSignatureLen(signatures, buffer)
+	switch {
+	case bomType == Unknown:
+		return -1
+	case size == 0:
+		return 0
 	}
-	return BomType[DetectBOMTypeFromBuffer(buffer)]
-}
 
-// TODO: Implement io.Reader detection
+	return bomType.Len()
+}
 
-//Read is an implementation of io.Reader interface.
-//The bytes are taken from Reader, checking for BOM and removing them if
-//necessary.
-func (r *Reader) Read(buffer []byte) (n int, err error) {
-	if len(buffer) == 0 {
-		return 0, nil
+// hasPrefix checks if data starts with the bytes of prefix, without converting
+// any of them.
+func hasPrefix[T ~string | ~[]byte](data T, prefix []byte) bool {
+	if len(data) < len(prefix) {
+		return false
 	}
 
-	// No initialization of the current reader?!
-	if r.buffer == nil {
-		if r.err != nil {
-			newErr := r.err
-			r.err = nil // we reports error, so no need to store it anymore
-			return 0, newErr
+	for i, b := range prefix {
+		if data[i] != b {
+			return false
 		}
-		return r.reader.Read(buffer)
 	}
-	n = copy(buffer, r.buffer)
-	return n, nil
+
+	return true
+}
+
+// DetectBOMTypeFromString detects the BOM type of s, without copying it into a
+// byte slice first.
+func DetectBOMTypeFromString(s string) BOMType {
+	return DetectFrom(s)
+}
+
+// IsBOMRune checks if r is the BOM rune (U+FEFF).
+func IsBOMRune(r rune) bool {
+	return r == BOMRune
+}
+
+// DetectBOMTypeFromRunes detects the BOM type of a rune slice.
+//
+// Go converts rune slices to and from strings using UTF-8, so a leading U+FEFF
+// is reported as UTF8, and Unknown is returned for anything else.
+func DetectBOMTypeFromRunes(runes []rune) BOMType {
+	if len(runes) > 0 && IsBOMRune(runes[0]) {
+		return UTF8
+	}
+
+	return Unknown
 }
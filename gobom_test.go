@@ -1,14 +1,377 @@
-/*
-TODO: Implement testing
-*/
 package gobom
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestDetectBOMTypeFromBytes(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   BOMType
+	}{
+		{nil, Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF}, Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}, UTF8},
+		{[]byte{0xFF, 0xFE, 'a', 0x00, 'b'}, UTF16LE},
+		{[]byte{0xFE, 0xFF, 0x00, 'a', 0x00}, UTF16BE},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00, 'a'}, UTF32LE},
+		{[]byte{0x00, 0x00, 0xFE, 0xFF, 0x00}, UTF32BE},
+		{[]byte("hello"), Unknown},
+	}
 
+	for _, test := range tests {
+		if got := DetectBOMTypeFromBytes(test.buffer); got != test.want {
+			t.Errorf("DetectBOMTypeFromBytes(%v) = %d, want %d", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestDetectBOMTypeFromBuffer(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   BOMType
+	}{
+		{nil, Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+		{[]byte{0xFF, 0xFE}, UTF16LE},
+		{[]byte{0xFF, 0xFE, 0x00}, UTF16LE},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00}, UTF32LE},
+		{[]byte{0x00, 0x00, 0xFE, 0xFF}, UTF32BE},
+		{[]byte{0x84, 0x31, 0x95, 0x33}, GB18030},
+		{[]byte{0x84, 0x31, 0x95}, Unknown},
+		{[]byte("+/v8-"), UTF7},
+		{[]byte("+/v9"), UTF7},
+		{[]byte("+/v"), Unknown},
+	}
+
+	for _, test := range tests {
+		if got := DetectBOMTypeFromBuffer(test.buffer); got != test.want {
+			t.Errorf("DetectBOMTypeFromBuffer(%v) = %d, want %d", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestDetectAllCandidates(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   []BOMType
+	}{
+		{nil, []BOMType{}},
+		{[]byte("hello"), []BOMType{}},
+		{[]byte{0xFF, 0xFE, 'a', 0x00}, []BOMType{UTF16LE}},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00}, []BOMType{UTF32LE, UTF16LE}},
+		{[]byte{0x00, 0x00, 0xFE, 0xFF}, []BOMType{UTF32BE}},
+		{[]byte("+/v8-"), []BOMType{UTF7}},
+	}
+
+	for _, test := range tests {
+		got := DetectAllCandidates(test.buffer)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("DetectAllCandidates(%v) = %v, want %v", test.buffer, got, test.want)
+		}
+	}
 }
 
 func TestIsUTF8BOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0xEF, 0xBB}, false},
+		{[]byte{0xEF, 0xBB, 0xBF}, true},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a'}, true},
+		{[]byte{0xEF, 0xBB, 'a', 0xBF}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsUTF8BOM(test.buffer); got != test.want {
+			t.Errorf("IsUTF8BOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestDetectBOMTypeFromString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want BOMType
+	}{
+		{"", Unknown},
+		{"hello", Unknown},
+		{"\xEF\xBB", Unknown},
+		{"\xEF\xBB\xBF", UTF8},
+		{"\xEF\xBB\xBFhello", UTF8},
+		{"\xFE\xFF\x00h", UTF16BE},
+		{"\x00\x00\xFE\xFF", UTF32BE},
+	}
+
+	for _, test := range tests {
+		if got := DetectBOMTypeFromString(test.s); got != test.want {
+			t.Errorf("DetectBOMTypeFromString(%q) = %d, want %d", test.s, got, test.want)
+		}
+	}
+}
+
+func BenchmarkDetectBOMTypeFromString(b *testing.B) {
+	s := "\xEF\xBB\xBFhello world"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DetectBOMTypeFromString(s)
+	}
+}
+
+func TestDetectBOMTypeFromRunes(t *testing.T) {
+	tests := []struct {
+		runes []rune
+		want  BOMType
+	}{
+		{nil, Unknown},
+		{[]rune("hello"), Unknown},
+		{[]rune("\uFEFFhello"), UTF8},
+		{[]rune("a\uFEFF"), Unknown},
+		{[]rune("ÿþabc"), Unknown},
+		{[]rune("ï»¿"), Unknown},
+		{[]rune{0xFE, 0xFF, 0x00, 'a'}, Unknown},
+	}
+
+	for _, test := range tests {
+		if got := DetectBOMTypeFromRunes(test.runes); got != test.want {
+			t.Errorf("DetectBOMTypeFromRunes(%v) = %d, want %d", test.runes, got, test.want)
+		}
+	}
+}
+
+func TestBytesToSkip(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   int
+	}{
+		{nil, -1},
+		{[]byte("hello"), -1},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a'}, 3},
+		{[]byte{0xFE, 0xFF, 0x00, 'a'}, 2},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00}, 4},
+		{[]byte("+/v8-hello"), 5},
+		{[]byte("+/v8AGE-"), 0},
+	}
+
+	for _, test := range tests {
+		if got := BytesToSkip(test.buffer); got != test.want {
+			t.Errorf("BytesToSkip(%v) = %d, want %d", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestDetectBOMTypeFromBytesE(t *testing.T) {
+	tests := []struct {
+		buffer  []byte
+		want    BOMType
+		wantErr error
+	}{
+		{nil, Unknown, ErrShortBuffer},
+		{[]byte{0xEF, 0xBB, 0xBF}, Unknown, ErrShortBuffer},
+		{[]byte("hello"), Unknown, ErrNoBOM},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}, UTF8, nil},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00, 'a'}, UTF32LE, nil},
+	}
+
+	for _, test := range tests {
+		got, err := DetectBOMTypeFromBytesE(test.buffer)
+		if got != test.want || !errors.Is(err, test.wantErr) {
+			t.Errorf("DetectBOMTypeFromBytesE(%v) = %s, %v, want %s, %v",
+				test.buffer, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestDetectBOMTypeFromBytesWithMode(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		mode   DetectionMode
+		want   BOMType
+	}{
+		{[]byte{0xEF, 0xBB, 0xBF}, MinLengthMode, Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF}, ExactLengthMode, UTF8},
+		{[]byte{0xEF, 0xBB}, ExactLengthMode, Unknown},
+		{[]byte{0xFE, 0xFF}, ExactLengthMode, UTF16BE},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00}, ExactLengthMode, UTF32LE},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}, MinLengthMode, UTF8},
+	}
+
+	for _, test := range tests {
+		if got := DetectBOMTypeFromBytesWithMode(test.buffer, test.mode); got != test.want {
+			t.Errorf("DetectBOMTypeFromBytesWithMode(%v, %d) = %s, want %s",
+				test.buffer, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestDetectFrom(t *testing.T) {
+	type body []byte
+	type text string
+
+	if got := DetectFrom(json.RawMessage("\xEF\xBB\xBF{}")); got != UTF8 {
+		t.Errorf("DetectFrom(json.RawMessage) = %s, want %s", got, UTF8)
+	}
+	if got := DetectFrom(body{0xFF, 0xFE, 0x00, 0x00}); got != UTF32LE {
+		t.Errorf("DetectFrom(body) = %s, want %s", got, UTF32LE)
+	}
+	if got := DetectFrom(text("\xFE\xFF")); got != UTF16BE {
+		t.Errorf("DetectFrom(text) = %s, want %s", got, UTF16BE)
+	}
+	if got := DetectFrom(""); got != Unknown {
+		t.Errorf("DetectFrom(\"\") = %s, want %s", got, Unknown)
+	}
+}
+
+func TestHasBOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte("hello"), false},
+		{[]byte{0xEF, 0xBB}, false},
+		{[]byte{0xEF, 0xBB, 0xBF}, true},
+		{[]byte{0xFE, 0xFF, 0x00, 'a'}, true},
+	}
+
+	for _, test := range tests {
+		if got := HasBOM(test.buffer); got != test.want {
+			t.Errorf("HasBOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsGB18030BOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0x84, 0x31, 0x95}, false},
+		{[]byte{0x84, 0x31, 0x95, 0x33}, true},
+		{[]byte{0x84, 0x31, 0x95, 0x33, 'a'}, true},
+		{[]byte{0x84, 0x31, 0x95, 0x34}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsGB18030BOM(test.buffer); got != test.want {
+			t.Errorf("IsGB18030BOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsUTF7BOM(t *testing.T) {
+	tests := []struct {
+		buffer string
+		want   bool
+	}{
+		{"", false},
+		{"+/v", false},
+		{"+/v8", true},
+		{"+/v9", true},
+		{"+/v+", true},
+		{"+/v/", true},
+		{"+/v8-", true},
+		{"+/v7", false},
+		{"+AGE-", false},
+	}
+
+	for _, test := range tests {
+		if got := IsUTF7BOM([]byte(test.buffer)); got != test.want {
+			t.Errorf("IsUTF7BOM(%q) = %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsUTF1BOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0xF7, 0x64}, false},
+		{[]byte{0xF7, 0x64, 0x4C}, true},
+		{[]byte{0xF7, 0x64, 0x4C, 0x61}, true},
+		{[]byte{0xF7, 0x64, 0x4D}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsUTF1BOM(test.buffer); got != test.want {
+			t.Errorf("IsUTF1BOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+		if got := DetectBOMTypeFromBuffer(test.buffer) == UTF1; got != test.want {
+			t.Errorf("DetectBOMTypeFromBuffer(%v) == UTF1 is %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsUTFEBCDICBOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0xDD, 0x73, 0x66}, false},
+		{[]byte{0xDD, 0x73, 0x66, 0x73}, true},
+		{[]byte{0xDD, 0x73, 0x66, 0x73, 0x61}, true},
+		{[]byte{0xDD, 0x73, 0x66, 0x74}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsUTFEBCDICBOM(test.buffer); got != test.want {
+			t.Errorf("IsUTFEBCDICBOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+		if got := DetectBOMTypeFromBuffer(test.buffer) == UTFEBCDIC; got != test.want {
+			t.Errorf("DetectBOMTypeFromBuffer(%v) == UTFEBCDIC is %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsSCSUBOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0x0E, 0xFE}, false},
+		{[]byte{0x0E, 0xFE, 0xFF}, true},
+		{[]byte{0x0E, 0xFE, 0xFF, 0x61}, true},
+		{[]byte{0x0E, 0xFE, 0x00}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsSCSUBOM(test.buffer); got != test.want {
+			t.Errorf("IsSCSUBOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+		if got := DetectBOMTypeFromBuffer(test.buffer) == SCSU; got != test.want {
+			t.Errorf("DetectBOMTypeFromBuffer(%v) == SCSU is %t, want %t", test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestIsBOCU1BOM(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		want   bool
+	}{
+		{nil, false},
+		{[]byte{0xFB, 0xEE}, false},
+		{[]byte{0xFB, 0xEE, 0x28}, true},
+		{[]byte{0xFB, 0xEE, 0x28, 0x61}, true},
+		{[]byte{0xFB, 0xEE, 0x29}, false},
+	}
 
+	for _, test := range tests {
+		if got := IsBOCU1BOM(test.buffer); got != test.want {
+			t.Errorf("IsBOCU1BOM(%v) = %t, want %t", test.buffer, got, test.want)
+		}
+		if got := DetectBOMTypeFromBuffer(test.buffer) == BOCU1; got != test.want {
+			t.Errorf("DetectBOMTypeFromBuffer(%v) == BOCU1 is %t, want %t", test.buffer, got, test.want)
+		}
+	}
 }
@@ -0,0 +1,180 @@
+package gobom
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// prober examines a sample, and returns the encodings it finds plausible. An
+// encoding that is not plausible at all is not returned. truncated tells that
+// the sample was cut from longer content.
+type prober func(sample []byte, truncated bool) []Guess
+
+// probers are the heuristics GuessEncodings combines
+var probers = []prober{
+	probeUTF8,
+	probeUTF16,
+	probeUTF32,
+	probeLegacy,
+}
+
+// GuessEncodings examines the first 8 KiB of buffer, or the limit that was set
+// by WithSniffLimit, and returns the encodings
+// it might be encoded with, ranked from the most plausible to the least.
+//
+// A BOM is the strongest evidence, and is ranked first with a confidence of 1,
+// while the confidence of any other guess is halved. Without a BOM, the
+// guesses are based on the validity of UTF-8, UTF-16 and UTF-32 content, and
+// on the frequency of high bit bytes for windows-1252 and ISO-8859-1.
+//
+// Guesses with a confidence lower than the minimum that was set by
+// WithMinConfidence are left out. An empty slice is returned when nothing is
+// plausible.
+func GuessEncodings(buffer []byte, opts ...HeuristicOption) []Guess {
+	return guessEncodings(buffer, false, newHeuristicOptions(opts))
+}
+
+// guessEncodings is GuessEncodings for a buffer that might have been cut from
+// longer content, as truncated tells
+func guessEncodings(buffer []byte, truncated bool, options heuristicOptions) []Guess {
+	bomType, size := matchSignatureLen(signatures, buffer)
+	content := buffer[size:]
+	truncated = truncated || len(content) > options.sniffLimit
+	buffer = sample(content, options.sniffLimit)
+
+	guesses := []Guess{}
+	for _, probe := range probers {
+		for _, guess := range probe(buffer, truncated) {
+			if bomType != Unknown {
+				if guess.Encoding == bomType.String() {
+					continue
+				}
+				guess.Confidence /= 2
+			}
+			guesses = append(guesses, guess)
+		}
+	}
+
+	sort.SliceStable(guesses, func(i, j int) bool {
+		return guesses[i].Confidence > guesses[j].Confidence
+	})
+
+	if bomType != Unknown {
+		guesses = append([]Guess{{Encoding: bomType.String(), Confidence: 1}}, guesses...)
+	}
+
+	for i, guess := range guesses {
+		if guess.Confidence < options.minConfidence {
+			return guesses[:i]
+		}
+	}
+
+	return guesses
+}
+
+// probeUTF8 finds UTF-8 plausible when the content is valid UTF-8
+func probeUTF8(sample []byte, truncated bool) []Guess {
+	confidence := utf8Confidence(sample, len(sample), truncated)
+	if len(sample) == 0 || confidence == 0 {
+		return nil
+	}
+
+	return []Guess{{Encoding: UTF8.String(), Confidence: confidence}}
+}
+
+// probeUTF16 finds UTF-16 plausible by the number of code units that hold an
+// ASCII or Latin-1 char, in each of the byte orders
+func probeUTF16(sample []byte, _ bool) []Guess {
+	units := len(sample) / 2
+	if units == 0 {
+		return nil
+	}
+
+	little, big := 0, 0
+	for i := 0; i+1 < len(sample); i += 2 {
+		switch {
+		case sample[i] != 0 && sample[i+1] == 0:
+			little++
+		case sample[i] == 0 && sample[i+1] != 0:
+			big++
+		}
+	}
+
+	guesses := []Guess{}
+	if little > 0 {
+		guesses = append(guesses, Guess{Encoding: UTF16LE.String(), Confidence: 0.95 * float64(little) / float64(units)})
+	}
+	if big > 0 {
+		guesses = append(guesses, Guess{Encoding: UTF16BE.String(), Confidence: 0.95 * float64(big) / float64(units)})
+	}
+
+	return guesses
+}
+
+// probeUTF32 finds UTF-32 plausible when every code unit holds a valid code
+// point, in each of the byte orders
+func probeUTF32(sample []byte, _ bool) []Guess {
+	units := len(sample) / 4
+	if units == 0 {
+		return nil
+	}
+
+	guesses := []Guess{}
+	for _, bomType := range []BOMType{UTF32LE, UTF32BE} {
+		order, _ := bomType.ByteOrder()
+
+		valid := 0
+		for i := 0; i+3 < len(sample); i += 4 {
+			r := rune(order.Uint32(sample[i:]))
+			if r == 0 {
+				continue
+			}
+			if r < 0 || !utf8.ValidRune(r) {
+				valid = 0
+				break
+			}
+			valid++
+		}
+
+		if valid > 0 {
+			guesses = append(guesses, Guess{Encoding: bomType.String(), Confidence: 0.98 * float64(valid) / float64(units)})
+		}
+	}
+
+	return guesses
+}
+
+// probeLegacy finds windows-1252 or ISO-8859-1 plausible by the frequency of
+// plausible high bit bytes, and lowers the confidence by the frequency of
+// control chars, that are rare in text
+func probeLegacy(sample []byte, truncated bool) []Guess {
+	if len(sample) == 0 {
+		return nil
+	}
+
+	var guess Guess
+	if confidence := utf8Confidence(sample, len(sample), truncated); confidence > 0 {
+		// valid UTF-8 might still be legacy content, but it is less likely
+		// the more multi-byte sequences it has
+		guess = Guess{Encoding: EncodingWindows1252, Confidence: 1 - confidence}
+		if confidence == 0.5 {
+			guess.Confidence = 0.49
+		}
+	} else {
+		guess = guessUTF8OrWindows1252(sample, len(sample), truncated)
+	}
+
+	controls := 0
+	for _, b := range sample {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			controls++
+		}
+	}
+	guess.Confidence *= 1 - float64(controls)/float64(len(sample))
+
+	if guess.Confidence <= 0 {
+		return nil
+	}
+
+	return []Guess{guess}
+}
@@ -0,0 +1,90 @@
+package gobom
+
+import "testing"
+
+// encodeUnits encodes ASCII text as UTF-16 or UTF-32 code units of size bytes
+func encodeUnits(s string, size int, bigEndian bool) []byte {
+	buffer := make([]byte, 0, len(s)*size)
+	for i := 0; i < len(s); i++ {
+		unit := make([]byte, size)
+		if bigEndian {
+			unit[size-1] = s[i]
+		} else {
+			unit[0] = s[i]
+		}
+		buffer = append(buffer, unit...)
+	}
+	return buffer
+}
+
+func TestGuessEncodings(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   string
+	}{
+		{"utf8", []byte("naïve café"), "UTF-8"},
+		{"utf8 bom", []byte("\xEF\xBB\xBFhello"), "UTF-8"},
+		{"utf16le bom", append([]byte{0xFF, 0xFE}, encodeUnits("hello", 2, false)...), "UTF-16LE"},
+		{"utf16le", encodeUnits("hello world", 2, false), "UTF-16LE"},
+		{"utf16be", encodeUnits("hello world", 2, true), "UTF-16BE"},
+		{"utf32le", encodeUnits("hello world", 4, false), "UTF-32LE"},
+		{"utf32be", encodeUnits("hello world", 4, true), "UTF-32BE"},
+		{"windows-1252", []byte("caf\xE9 \x93cr\xE8me\x94"), EncodingWindows1252},
+		{"latin1", []byte("caf\xE9 \x81"), EncodingISO88591},
+		{"windows-1252 at the end", []byte("caf\xE9"), EncodingWindows1252},
+	}
+
+	for _, test := range tests {
+		guesses := GuessEncodings(test.buffer)
+		if len(guesses) == 0 {
+			t.Errorf("%s: no guesses", test.name)
+			continue
+		}
+		if guesses[0].Encoding != test.want {
+			t.Errorf("%s: got %+v, want %s first", test.name, guesses, test.want)
+		}
+		for i := 1; i < len(guesses); i++ {
+			if guesses[i].Confidence > guesses[i-1].Confidence {
+				t.Errorf("%s: guesses are not ranked: %+v", test.name, guesses)
+			}
+		}
+	}
+
+	if guesses := GuessEncodings(nil); len(guesses) != 0 {
+		t.Errorf("empty: got %+v, want no guesses", guesses)
+	}
+
+	guesses := GuessEncodings([]byte("\xEF\xBB\xBFhello"))
+	if guesses[0].Confidence != 1 {
+		t.Errorf("bom: got confidence %f, want 1", guesses[0].Confidence)
+	}
+}
+
+func TestGuessEncodingsMinConfidence(t *testing.T) {
+	buffer := []byte("\xEF\xBB\xBFhello")
+	if got := GuessEncodings(buffer, WithMinConfidence(1)); len(got) != 1 || got[0].Encoding != "UTF-8" {
+		t.Errorf("GuessEncodings(%q, 1) = %+v, want only UTF-8", buffer, got)
+	}
+
+	buffer = []byte("hello")
+	if got := GuessEncodings(buffer, WithMinConfidence(1)); len(got) != 0 {
+		t.Errorf("GuessEncodings(%q, 1) = %+v, want none", buffer, got)
+	}
+
+	for _, guess := range GuessEncodings(buffer, WithMinConfidence(0.3)) {
+		if guess.Confidence < 0.3 {
+			t.Errorf("GuessEncodings(%q, 0.3) returned %+v", buffer, guess)
+		}
+	}
+}
+
+func TestGuessEncodingsSniffLimit(t *testing.T) {
+	buffer := append([]byte("hello "), "naïve"...)
+
+	for _, guess := range GuessEncodings(buffer, WithSniffLimit(6)) {
+		if guess.Encoding == "UTF-8" && guess.Confidence != 0.5 {
+			t.Errorf("GuessEncodings(%q, 6) = %+v, want ASCII only confidence", buffer, guess)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package gobom
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// sampleSize is the number of bytes the heuristics examine out of a buffer,
+// unless WithSniffLimit sets another limit
+const sampleSize = 8 * 1024
+
+// sample returns the part of buffer the heuristics examine, which is up to
+// limit bytes
+func sample(buffer []byte, limit int) []byte {
+	if len(buffer) > limit {
+		return buffer[:limit]
+	}
+
+	return buffer
+}
+
+// sniff reads the BOM of r, and up to limit bytes of content after it, even
+// if r returns them one at a time. truncated is true when r has more content
+// than limit bytes. Reaching the end of r is not an error.
+func sniff(r io.Reader, limit int) (buffer []byte, truncated bool, err error) {
+	buffer, err = readHeader(r, maxBOMLen)
+	if err != nil {
+		if err == io.EOF {
+			return buffer, false, nil
+		}
+		return nil, false, err
+	}
+
+	_, size := matchSignatureLen(signatures, buffer)
+	if content := len(buffer) - size; content <= limit {
+		// a byte past the limit tells if there is more content
+		rest, err := readHeader(r, limit-content+1)
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		buffer = append(buffer, rest...)
+	}
+
+	if len(buffer)-size > limit {
+		return buffer[:size+limit], true, nil
+	}
+
+	return buffer, false, nil
+}
+
+// scanUTF8 validates the multi-byte sequences of buffer, and returns the
+// number of multi-byte sequences that were found. When buffer was truncated
+// from longer content, a sequence that was cut by the end of buffer is not
+// considered to be invalid.
+func scanUTF8(buffer []byte, truncated bool) (multiByte int, valid bool) {
+
+	for i := 0; i < len(buffer); {
+		if buffer[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(buffer[i:])
+		if r == utf8.RuneError && size <= 1 {
+			if truncated && !utf8.FullRune(buffer[i:]) {
+				break
+			}
+			return multiByte, false
+		}
+
+		multiByte++
+		i += size
+	}
+
+	return multiByte, true
+}
+
+// LooksLikeUTF8 checks if buffer looks like UTF-8 content, with or without a
+// BOM, by validating the multi-byte sequences of the first 8 KiB of it.
+//
+// Note that ASCII content is also valid UTF-8, use UTF8Confidence in order to
+// tell how likely the content is UTF-8 rather than a legacy encoding.
+func LooksLikeUTF8(buffer []byte) bool {
+	_, valid := scanUTF8(sample(buffer, sampleSize), len(buffer) > sampleSize)
+	return valid
+}
+
+// UTF8Confidence returns how likely buffer is UTF-8 content, between 0 and 1.
+//
+// Content with invalid sequences returns 0, and ASCII only content returns
+// 0.5, because it might be any ASCII based encoding. Every valid multi-byte
+// sequence raises the confidence, and a UTF-8 BOM makes it certain (1).
+func UTF8Confidence(buffer []byte) float64 {
+	return utf8Confidence(buffer, sampleSize, false)
+}
+
+// utf8Confidence is UTF8Confidence that examines up to limit bytes. truncated
+// tells that buffer itself was cut from longer content.
+func utf8Confidence(buffer []byte, limit int, truncated bool) float64 {
+	bom := IsUTF8BOM(buffer)
+	if bom {
+		buffer = buffer[len(UTF8Bom):]
+	}
+
+	multiByte, valid := scanUTF8(sample(buffer, limit), truncated || len(buffer) > limit)
+	switch {
+	case !valid:
+		return 0
+	case bom:
+		return 1
+	}
+
+	confidence := 0.5
+	for i := 0; i < multiByte && confidence < 0.99; i++ {
+		confidence += (1 - confidence) / 2
+	}
+
+	return confidence
+}
+
+// Names of the encodings the heuristics report
+const (
+	EncodingUTF8        = "UTF-8"
+	EncodingWindows1252 = "windows-1252"
+	EncodingISO88591    = "ISO-8859-1"
+)
+
+// Guess is an encoding that a heuristic found to be plausible
+type Guess struct {
+	// Encoding is the name of the encoding, such as "UTF-8"
+	Encoding string
+	// Confidence tells how plausible the encoding is, between 0 and 1
+	Confidence float64
+}
+
+// HeuristicOption configures the heuristics
+type HeuristicOption func(*heuristicOptions)
+
+// heuristicOptions holds the configuration of the heuristics
+type heuristicOptions struct {
+	minConfidence float64
+	sniffLimit    int
+}
+
+// newHeuristicOptions returns the configuration opts make
+func newHeuristicOptions(opts []HeuristicOption) heuristicOptions {
+	options := heuristicOptions{sniffLimit: sampleSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithMinConfidence makes the heuristics report only guesses with a confidence
+// of at least min. The default is 0, that reports the best guesses even when
+// they are far from certain, while 1 reports only certain answers, such as a
+// BOM.
+func WithMinConfidence(min float64) HeuristicOption {
+	return func(o *heuristicOptions) {
+		o.minConfidence = min
+	}
+}
+
+// WithSniffLimit sets the number of bytes the heuristics examine to n, instead
+// of the first 8 KiB. Analyzers that read from an io.Reader read only the BOM
+// and n bytes after it, so their memory use does not depend on the size of the
+// content. A limit that is not positive keeps the default.
+func WithSniffLimit(n int) HeuristicOption {
+	return func(o *heuristicOptions) {
+		if n > 0 {
+			o.sniffLimit = n
+		}
+	}
+}
+
+// isUndefinedWindows1252 checks if b has no char at windows-1252
+func isUndefinedWindows1252(b byte) bool {
+	return b == 0x81 || b == 0x8D || b == 0x8F || b == 0x90 || b == 0x9D
+}
+
+// isPlausibleWindows1252 checks if the high bit byte b is a char that is
+// common in text that is encoded using windows-1252: letters, quotes, dashes
+// and the euro sign
+func isPlausibleWindows1252(b byte) bool {
+	switch {
+	case b >= 0xC0 && b != 0xD7 && b != 0xF7:
+		return true
+	case b >= 0x91 && b <= 0x97, b == 0x80, b == 0x85:
+		return true
+	case b == 0x8A, b == 0x8C, b == 0x8E, b == 0x9A, b == 0x9C, b == 0x9E, b == 0x9F:
+		return true
+	case b == 0xA0, b == 0xA3, b == 0xA9, b == 0xAB, b == 0xB0, b == 0xBB:
+		return true
+	}
+
+	return false
+}
+
+// GuessUTF8OrWindows1252 decides whether buffer, that has no BOM, is more
+// likely UTF-8 or windows-1252, by examining the high bit bytes of the first
+// 8 KiB of it.
+//
+// Valid UTF-8 multi-byte sequences make UTF-8 the answer. Otherwise, the high
+// bit bytes are examined as windows-1252 chars, and content that uses bytes
+// that windows-1252 does not define is reported as ISO-8859-1 instead.
+// ASCII only content is reported as UTF-8 with a confidence of 0.5.
+//
+// An empty Guess is returned when the confidence is lower than the minimum
+// that was set by WithMinConfidence.
+func GuessUTF8OrWindows1252(buffer []byte, opts ...HeuristicOption) Guess {
+	options := newHeuristicOptions(opts)
+
+	guess := guessUTF8OrWindows1252(buffer, options.sniffLimit, false)
+	if guess.Confidence < options.minConfidence {
+		return Guess{}
+	}
+
+	return guess
+}
+
+// guessUTF8OrWindows1252 is GuessUTF8OrWindows1252 without a minimum, that
+// examines up to limit bytes. truncated tells that buffer itself was cut from
+// longer content.
+func guessUTF8OrWindows1252(buffer []byte, limit int, truncated bool) Guess {
+	if confidence := utf8Confidence(buffer, limit, truncated); confidence > 0 {
+		return Guess{Encoding: EncodingUTF8, Confidence: confidence}
+	}
+
+	high, plausible := 0, 0
+	encoding := EncodingWindows1252
+	for _, b := range sample(buffer, limit) {
+		if b < 0x80 {
+			continue
+		}
+
+		high++
+		if isUndefinedWindows1252(b) {
+			encoding = EncodingISO88591
+		}
+		if isPlausibleWindows1252(b) {
+			plausible++
+		}
+	}
+
+	confidence := 0.5 + 0.49*float64(plausible)/float64(high)
+	return Guess{Encoding: encoding, Confidence: confidence}
+}
@@ -0,0 +1,101 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLooksLikeUTF8(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   bool
+	}{
+		{"empty", nil, true},
+		{"ascii", []byte("hello"), true},
+		{"bom", []byte("\xEF\xBB\xBFhello"), true},
+		{"multi byte", []byte("שלום"), true},
+		{"cut at the end", []byte("שלום")[:3], false},
+		{"latin1", []byte("caf\xE9 au lait"), false},
+		{"latin1 at the end", []byte("caf\xE9"), false},
+		{"invalid continuation", []byte("\xC3\x28"), false},
+		{"cut by the sample", append(bytes.Repeat([]byte("a"), sampleSize-1), "שלום"...), true},
+		{"invalid after the sample", append(bytes.Repeat([]byte("a"), sampleSize), 0xFF), true},
+	}
+
+	for _, test := range tests {
+		if got := LooksLikeUTF8(test.buffer); got != test.want {
+			t.Errorf("%s: LooksLikeUTF8() = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUTF8Confidence(t *testing.T) {
+	if got := UTF8Confidence([]byte("caf\xE9 au lait")); got != 0 {
+		t.Errorf("invalid: got %f, want 0", got)
+	}
+	if got := UTF8Confidence([]byte("hello")); got != 0.5 {
+		t.Errorf("ascii: got %f, want 0.5", got)
+	}
+	if got := UTF8Confidence([]byte("\xEF\xBB\xBFhello")); got != 1 {
+		t.Errorf("bom: got %f, want 1", got)
+	}
+
+	one := UTF8Confidence([]byte("café"))
+	many := UTF8Confidence([]byte("café crème brûlée"))
+	if one <= 0.5 || many <= one || many >= 1 {
+		t.Errorf("got %f for one sequence and %f for many", one, many)
+	}
+}
+
+func TestGuessUTF8OrWindows1252(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffer   []byte
+		encoding string
+	}{
+		{"empty", nil, EncodingUTF8},
+		{"ascii", []byte("name,age"), EncodingUTF8},
+		{"utf8", []byte("naïve café"), EncodingUTF8},
+		{"windows-1252", []byte("na\xEFve caf\xE9 \x93quoted\x94"), EncodingWindows1252},
+		{"latin1", []byte("caf\xE9 \x81"), EncodingISO88591},
+		{"windows-1252 at the end", []byte("caf\xE9"), EncodingWindows1252},
+	}
+
+	for _, test := range tests {
+		got := GuessUTF8OrWindows1252(test.buffer)
+		if got.Encoding != test.encoding {
+			t.Errorf("%s: got %+v, want %s", test.name, got, test.encoding)
+		}
+		if got.Confidence <= 0 || got.Confidence > 1 {
+			t.Errorf("%s: confidence %f is out of range", test.name, got.Confidence)
+		}
+	}
+
+	plausible := GuessUTF8OrWindows1252([]byte("caf\xE9 cr\xE8me"))
+	implausible := GuessUTF8OrWindows1252([]byte("\xA4\xA6\xB5\xB6"))
+	if plausible.Confidence <= implausible.Confidence {
+		t.Errorf("plausible text got %f, and implausible got %f", plausible.Confidence, implausible.Confidence)
+	}
+}
+
+func TestGuessUTF8OrWindows1252MinConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffer   []byte
+		min      float64
+		encoding string
+	}{
+		{"ascii default", []byte("name,age"), 0, EncodingUTF8},
+		{"ascii uncertain", []byte("name,age"), 0.9, ""},
+		{"utf8 bom certain", []byte("\xEF\xBB\xBFname"), 1, EncodingUTF8},
+		{"utf8 uncertain", []byte("naïve"), 1, ""},
+	}
+
+	for _, test := range tests {
+		got := GuessUTF8OrWindows1252(test.buffer, WithMinConfidence(test.min))
+		if got.Encoding != test.encoding {
+			t.Errorf("%s: got %+v, want %q", test.name, got, test.encoding)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package gobom
+
+import (
+	"bytes"
+	"strings"
+)
+
+// htmlPrescanSize is the number of bytes the WHATWG prescan examines
+const htmlPrescanSize = 1024
+
+// htmlDefaultEncoding is the encoding of HTML content, when no other encoding
+// was found
+const htmlDefaultEncoding = "windows-1252"
+
+// EncodingSource tells where an encoding decision came from
+type EncodingSource uint8
+
+// Enumeration of the sources of an encoding decision
+const (
+	// SourceDefault means that nothing declared the encoding, so a default
+	// was used
+	SourceDefault EncodingSource = iota
+	// SourceBOM means that the encoding was decided by a BOM
+	SourceBOM
+	// SourceMeta means that the encoding was declared by an HTML meta tag
+	SourceMeta
+	// SourceHeader means that the encoding was declared by the charset of a
+	// Content-Type header
+	SourceHeader
+)
+
+// String is an implementation of fmt.Stringer interface
+func (s EncodingSource) String() string {
+	switch s {
+	case SourceBOM:
+		return "BOM"
+	case SourceMeta:
+		return "meta"
+	case SourceHeader:
+		return "header"
+	}
+
+	return "default"
+}
+
+// HTMLEncoding holds the encoding decision of an HTML document
+type HTMLEncoding struct {
+	// Encoding is the lower case label of the encoding, such as "utf-8"
+	Encoding string
+	// Source tells what decided the encoding
+	Source EncodingSource
+	// BOM is the BOM that decided the encoding, or Unknown
+	BOM BOMType
+}
+
+// SniffHTML decides the encoding of an HTML document as described by the
+// WHATWG encoding sniffing algorithm: a UTF-8 or UTF-16 BOM decides first,
+// then a <meta charset> or <meta http-equiv="Content-Type"> at the first 1024
+// bytes of the document, and otherwise "windows-1252" is used.
+//
+// As in the WHATWG algorithm, only UTF-8 and UTF-16 BOMs are honored, and a
+// meta tag that declares UTF-16 is taken as UTF-8. The label of the meta tag
+// is returned in lower case, and is not validated.
+func SniffHTML(buffer []byte) HTMLEncoding {
+	switch {
+	case bytes.HasPrefix(buffer, UTF8Bom):
+		return HTMLEncoding{Encoding: "utf-8", Source: SourceBOM, BOM: UTF8}
+	case bytes.HasPrefix(buffer, UTF16BEBom):
+		return HTMLEncoding{Encoding: "utf-16be", Source: SourceBOM, BOM: UTF16BE}
+	case bytes.HasPrefix(buffer, UTF16LEBom):
+		return HTMLEncoding{Encoding: "utf-16le", Source: SourceBOM, BOM: UTF16LE}
+	}
+
+	if len(buffer) > htmlPrescanSize {
+		buffer = buffer[:htmlPrescanSize]
+	}
+
+	if charset := prescanHTML(buffer); charset != "" {
+		return HTMLEncoding{Encoding: charset, Source: SourceMeta}
+	}
+
+	return HTMLEncoding{Encoding: htmlDefaultEncoding, Source: SourceDefault}
+}
+
+// isHTMLSpace checks if b is a white space char of the WHATWG prescan
+func isHTMLSpace(b byte) bool {
+	return b == 0x09 || b == 0x0A || b == 0x0C || b == 0x0D || b == 0x20
+}
+
+// isASCIILetter checks if b is an ASCII letter
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// hasPrefixFold checks if buffer starts with prefix, that must be lower case,
+// ignoring the case of ASCII letters
+func hasPrefixFold(buffer []byte, prefix string) bool {
+	return len(buffer) >= len(prefix) && strings.EqualFold(string(buffer[:len(prefix)]), prefix)
+}
+
+// prescanHTML returns the charset that is declared by a meta tag of buffer,
+// or an empty string
+func prescanHTML(buffer []byte) string {
+	for position := 0; position < len(buffer); position++ {
+		rest := buffer[position:]
+
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest[2:], []byte("-->"))
+			if end < 0 {
+				return ""
+			}
+			position += 2 + end + 2
+
+		case hasPrefixFold(rest, "<meta") && len(rest) > 5 && (isHTMLSpace(rest[5]) || rest[5] == '/'):
+			position += 6
+			charset, next := htmlMetaCharset(buffer, position)
+			if charset != "" {
+				return charset
+			}
+			position = next - 1
+
+		case len(rest) > 2 && rest[0] == '<' &&
+			(isASCIILetter(rest[1]) || (rest[1] == '/' && isASCIILetter(rest[2]))):
+			for position < len(buffer) && !isHTMLSpace(buffer[position]) && buffer[position] != '>' {
+				position++
+			}
+			for {
+				var ok bool
+				_, _, position, ok = htmlAttribute(buffer, position)
+				if !ok {
+					break
+				}
+			}
+
+		case bytes.HasPrefix(rest, []byte("<!")) || bytes.HasPrefix(rest, []byte("</")) ||
+			bytes.HasPrefix(rest, []byte("<?")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return ""
+			}
+			position += end
+		}
+	}
+
+	return ""
+}
+
+// htmlMetaCharset reads the attributes of a meta tag from position, and
+// returns the charset it declares (or an empty string), and the position after
+// the attributes
+func htmlMetaCharset(buffer []byte, position int) (string, int) {
+	seen := map[string]bool{}
+	gotPragma := false
+	needPragma := 0 // 0 is unset, 1 is false, 2 is true
+	charset := ""
+
+	for {
+		name, value, next, ok := htmlAttribute(buffer, position)
+		position = next
+		if !ok {
+			break
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case "http-equiv":
+			if value == "content-type" {
+				gotPragma = true
+			}
+		case "content":
+			if charset == "" {
+				if found := charsetFromContentType(value); found != "" {
+					charset = found
+					needPragma = 2
+				}
+			}
+		case "charset":
+			if charset == "" {
+				charset = value
+				needPragma = 1
+			}
+		}
+	}
+
+	if needPragma == 0 || (needPragma == 2 && !gotPragma) || charset == "" {
+		return "", position
+	}
+
+	charset = strings.TrimSpace(charset)
+	switch charset {
+	case "utf-16", "utf-16be", "utf-16le":
+		charset = "utf-8"
+	case "x-user-defined":
+		charset = htmlDefaultEncoding
+	}
+
+	return charset, position
+}
+
+// htmlAttribute reads a single attribute of a tag from position, as described
+// by the "get an attribute" algorithm of WHATWG. The name and value are in
+// lower case. If there are no more attributes, ok is false.
+func htmlAttribute(buffer []byte, position int) (name, value string, next int, ok bool) {
+	for position < len(buffer) && (isHTMLSpace(buffer[position]) || buffer[position] == '/') {
+		position++
+	}
+	if position >= len(buffer) || buffer[position] == '>' {
+		return "", "", position, false
+	}
+
+	var nameBytes []byte
+	for position < len(buffer) {
+		b := buffer[position]
+		if (b == '=' && len(nameBytes) > 0) || isHTMLSpace(b) || b == '/' || b == '>' {
+			break
+		}
+
+		nameBytes = append(nameBytes, toLowerASCII(b))
+		position++
+	}
+	if position >= len(buffer) {
+		return "", "", position, false
+	}
+
+	name = string(nameBytes)
+	if buffer[position] == '/' || buffer[position] == '>' {
+		return name, "", position, true
+	}
+
+	for position < len(buffer) && isHTMLSpace(buffer[position]) {
+		position++
+	}
+	if position >= len(buffer) || buffer[position] != '=' {
+		return name, "", position, true
+	}
+	position++
+
+	for position < len(buffer) && isHTMLSpace(buffer[position]) {
+		position++
+	}
+	if position >= len(buffer) {
+		return "", "", position, false
+	}
+
+	var valueBytes []byte
+	switch quote := buffer[position]; quote {
+	case '"', '\'':
+		position++
+		for position < len(buffer) && buffer[position] != quote {
+			valueBytes = append(valueBytes, toLowerASCII(buffer[position]))
+			position++
+		}
+		if position >= len(buffer) {
+			return "", "", position, false
+		}
+		return name, string(valueBytes), position + 1, true
+	case '>':
+		return name, "", position, true
+	}
+
+	for position < len(buffer) && !isHTMLSpace(buffer[position]) && buffer[position] != '>' {
+		valueBytes = append(valueBytes, toLowerASCII(buffer[position]))
+		position++
+	}
+
+	return name, string(valueBytes), position, true
+}
+
+// toLowerASCII returns the lower case of an ASCII letter, and any other byte
+// as is
+func toLowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+
+	return b
+}
+
+// charsetFromContentType extracts the charset parameter out of a
+// Content-Type value, as described by the "extracting a character encoding
+// from a meta element" algorithm of WHATWG. It returns an empty string if
+// there is none.
+func charsetFromContentType(value string) string {
+	lower := strings.ToLower(value)
+
+	for {
+		i := strings.Index(lower, "charset")
+		if i < 0 {
+			return ""
+		}
+
+		lower = strings.TrimLeft(lower[i+len("charset"):], "\t\n\f\r ")
+		if !strings.HasPrefix(lower, "=") {
+			continue
+		}
+
+		lower = strings.TrimLeft(lower[1:], "\t\n\f\r ")
+		if lower == "" {
+			return ""
+		}
+
+		if quote := lower[0]; quote == '"' || quote == '\'' {
+			end := strings.IndexByte(lower[1:], quote)
+			if end < 0 {
+				return ""
+			}
+			return lower[1 : 1+end]
+		}
+
+		end := strings.IndexAny(lower, "\t\n\f\r ;")
+		if end < 0 {
+			return lower
+		}
+		return lower[:end]
+	}
+}
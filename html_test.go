@@ -0,0 +1,68 @@
+package gobom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniffHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer string
+		want   HTMLEncoding
+	}{
+		{"empty", "", HTMLEncoding{Encoding: "windows-1252"}},
+		{"no meta", "<html><body>hi</body></html>", HTMLEncoding{Encoding: "windows-1252"}},
+		{"utf8 bom", "\xEF\xBB\xBF<html>", HTMLEncoding{Encoding: "utf-8", Source: SourceBOM, BOM: UTF8}},
+		{
+			"utf16le bom wins over meta",
+			"\xFF\xFE<\x00m\x00",
+			HTMLEncoding{Encoding: "utf-16le", Source: SourceBOM, BOM: UTF16LE},
+		},
+		{"meta charset", `<html><head><meta charset="UTF-8">`, HTMLEncoding{Encoding: "utf-8", Source: SourceMeta}},
+		{"meta charset unquoted", `<meta charset=iso-8859-1>`, HTMLEncoding{Encoding: "iso-8859-1", Source: SourceMeta}},
+		{
+			"http equiv",
+			`<meta http-equiv="Content-Type" content="text/html; charset=Shift_JIS">`,
+			HTMLEncoding{Encoding: "shift_jis", Source: SourceMeta},
+		},
+		{
+			"content without pragma",
+			`<meta content="text/html; charset=koi8-r"><meta charset="utf-8">`,
+			HTMLEncoding{Encoding: "utf-8", Source: SourceMeta},
+		},
+		{
+			"content before charset",
+			`<meta http-equiv="content-type" content="text/html; charset=iso-8859-2" charset="utf-8">`,
+			HTMLEncoding{Encoding: "iso-8859-2", Source: SourceMeta},
+		},
+		{
+			"content before charset without pragma",
+			`<meta content="text/html; charset=iso-8859-2" charset="utf-8">`,
+			HTMLEncoding{Encoding: "windows-1252"},
+		},
+		{
+			"inside a comment",
+			`<!-- <meta charset="koi8-r"> --><meta charset="utf-8">`,
+			HTMLEncoding{Encoding: "utf-8", Source: SourceMeta},
+		},
+		{
+			"inside an attribute",
+			`<div title='<meta charset="koi8-r">'><meta charset="utf-8">`,
+			HTMLEncoding{Encoding: "utf-8", Source: SourceMeta},
+		},
+		{"utf16 declared", `<meta charset="utf-16">`, HTMLEncoding{Encoding: "utf-8", Source: SourceMeta}},
+		{"upper case", `<META CHARSET="EUC-JP">`, HTMLEncoding{Encoding: "euc-jp", Source: SourceMeta}},
+		{
+			"after the prescan",
+			strings.Repeat(" ", htmlPrescanSize) + `<meta charset="utf-8">`,
+			HTMLEncoding{Encoding: "windows-1252"},
+		},
+	}
+
+	for _, test := range tests {
+		if got := SniffHTML([]byte(test.buffer)); got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gobom
+
+// IncrementalDetector detects the BOM of content that is pushed to it in
+// chunks of any size, for event driven code, such as proxies and packet
+// handlers, that does not read from an io.Reader.
+//
+// The zero value is ready to use. An IncrementalDetector is not safe for
+// concurrent use.
+type IncrementalDetector struct {
+	header  [maxBOMLen]byte
+	size    int
+	done    bool
+	bomType BOMType
+	bomLen  int
+}
+
+// NewIncrementalDetector creates a new IncrementalDetector
+func NewIncrementalDetector() *IncrementalDetector {
+	return &IncrementalDetector{}
+}
+
+// Feed pushes the next chunk of the content, and returns true with the BOM
+// type once enough bytes were pushed in order to decide. Until then it returns
+// false with Unknown, and once decided, the chunks that follow are ignored.
+func (d *IncrementalDetector) Feed(chunk []byte) (bool, BOMType) {
+	if !d.done {
+		d.size += copy(d.header[d.size:], chunk)
+		if bomDecided(d.header[:d.size]) {
+			d.decide()
+		}
+	}
+
+	return d.done, d.bomType
+}
+
+// Finish decides the BOM type by the bytes that were pushed, when the content
+// ended before Feed decided, in the same manner as a Reader at the end of its
+// content.
+func (d *IncrementalDetector) Finish() BOMType {
+	if !d.done {
+		d.decide()
+	}
+
+	return d.bomType
+}
+
+// Len returns the number of bytes the BOM takes, so it can be removed from the
+// content, or 0 when there is no BOM or it was not decided yet.
+func (d *IncrementalDetector) Len() int {
+	return d.bomLen
+}
+
+// Reset discards the state of d, so it can detect the BOM of new content.
+func (d *IncrementalDetector) Reset() {
+	*d = IncrementalDetector{}
+}
+
+// decide detects the BOM type out of the bytes that were pushed
+func (d *IncrementalDetector) decide() {
+	d.done = true
+	d.bomType, d.bomLen = matchSignatureLen(signatures, d.header[:d.size])
+}
@@ -0,0 +1,50 @@
+package gobom
+
+import "testing"
+
+func TestIncrementalDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunks  [][]byte
+		wantAt  int
+		want    BOMType
+		wantLen int
+	}{
+		{"no bom", [][]byte{[]byte("hello")}, 0, Unknown, 0},
+		{"utf8 one chunk", [][]byte{[]byte("\xEF\xBB\xBFhi")}, 0, UTF8, 3},
+		{"utf8 split", [][]byte{{0xEF}, {}, {0xBB}, {0xBF}, []byte("hi")}, 3, UTF8, 3},
+		{"utf32le split", [][]byte{{0xFF, 0xFE}, {0x00}, {0x00, 'h'}}, 2, UTF32LE, 4},
+		{"utf16le", [][]byte{{0xFF, 0xFE}, {'h'}}, 1, UTF16LE, 2},
+		{"undecided", [][]byte{{0xFF, 0xFE}}, -1, UTF16LE, 2},
+	}
+
+	for _, test := range tests {
+		d := NewIncrementalDetector()
+
+		at := -1
+		for i, chunk := range test.chunks {
+			done, bomType := d.Feed(chunk)
+			if done && at < 0 {
+				at = i
+				if bomType != test.want {
+					t.Errorf("%s: Feed() = %s, want %s", test.name, bomType, test.want)
+				}
+			}
+			if !done && bomType != Unknown {
+				t.Errorf("%s: Feed() = false, %s, want Unknown", test.name, bomType)
+			}
+		}
+
+		if at != test.wantAt {
+			t.Errorf("%s: decided at chunk %d, want %d", test.name, at, test.wantAt)
+		}
+		if got := d.Finish(); got != test.want || d.Len() != test.wantLen {
+			t.Errorf("%s: Finish() = %s, Len() = %d, want %s, %d", test.name, got, d.Len(), test.want, test.wantLen)
+		}
+
+		d.Reset()
+		if done, bomType := d.Feed([]byte("abc")); !done || bomType != Unknown || d.Len() != 0 {
+			t.Errorf("%s: Feed() after Reset() = %t, %s, want true, Unknown", test.name, done, bomType)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+)
+
+// encodedBOMRune returns how the BOM rune is encoded by t, and the size of the
+// code unit of t. Anything but UTF-16 and UTF-32 is considered UTF-8.
+func encodedBOMRune(t BOMType) ([]byte, int) {
+	switch t {
+	case UTF16LE, UTF16BE:
+		return signatureOf(t), 2
+	case UTF32LE, UTF32BE:
+		return signatureOf(t), 4
+	}
+
+	return UTF8Bom, 1
+}
+
+// FindInteriorBOMs returns the offsets of the BOM chars (U+FEFF) that buffer
+// holds after its beginning, such as those that are left when BOM prefixed
+// files are concatenated. A BOM at the beginning of buffer is not reported.
+//
+// The encoding of buffer is decided by the BOM at its beginning, and it is
+// considered UTF-8 when there is none. In UTF-16 and UTF-32 content, only BOM
+// chars that are aligned to code units are reported.
+func FindInteriorBOMs(buffer []byte) []int {
+	bomType, size := matchSignatureLen(signatures, buffer)
+	seq, unit := encodedBOMRune(bomType)
+
+	offsets := []int{}
+	if unit == 1 {
+		for offset := size; ; {
+			i := bytes.Index(buffer[offset:], seq)
+			if i < 0 {
+				break
+			}
+			offsets = append(offsets, offset+i)
+			offset += i + len(seq)
+		}
+		return offsets
+	}
+
+	for offset := size; offset+unit <= len(buffer); offset += unit {
+		if bytes.Equal(buffer[offset:offset+unit], seq) {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	return offsets
+}
+
+// interiorReader removes the interior BOM chars of a reader
+type interiorReader struct {
+	reader  io.Reader
+	started bool
+	seq     []byte
+	unit    int
+	pending []byte
+	out     []byte
+	chunk   []byte
+	err     error
+}
+
+// NewInteriorBOMReader returns a reader that removes the BOM chars (U+FEFF)
+// that r holds after its beginning, for UTF-8, UTF-16 and UTF-32 content. The
+// BOM at the beginning of r, if any, is kept, and decides the encoding in the
+// same manner as FindInteriorBOMs.
+//
+// BOM chars that are split between reads of r are removed as well.
+func NewInteriorBOMReader(r io.Reader) io.Reader {
+	return &interiorReader{reader: r}
+}
+
+// Read is an implementation of io.Reader interface
+func (i *interiorReader) Read(buffer []byte) (int, error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if !i.started {
+		i.started = true
+
+		header, err := readHeader(i.reader, maxBOMLen)
+		bomType, size := matchSignatureLen(signatures, header)
+		i.seq, i.unit = encodedBOMRune(bomType)
+		i.out = header[:size]
+		i.pending = header[size:]
+		i.err = err
+		i.filter()
+	}
+
+	for len(i.out) == 0 && i.err == nil {
+		size := len(buffer) + len(i.seq)
+		if cap(i.chunk) < size {
+			i.chunk = make([]byte, size)
+		}
+		n, err := i.reader.Read(i.chunk[:size])
+		i.pending = append(i.pending, i.chunk[:n]...)
+		i.err = err
+		i.filter()
+	}
+
+	if len(i.out) > 0 {
+		n := copy(buffer, i.out)
+		i.out = i.out[n:]
+		return n, nil
+	}
+
+	return 0, i.err
+}
+
+// filter moves the pending bytes to the output without the BOM chars. Bytes
+// that might be the beginning of a BOM char stay pending, until more bytes are
+// read, or the reader ended.
+func (i *interiorReader) filter() {
+	final := i.err != nil
+	data := i.pending
+	keep := 0
+
+	if i.unit == 1 {
+		for {
+			index := bytes.Index(data, i.seq)
+			if index < 0 {
+				break
+			}
+			i.out = append(i.out, data[:index]...)
+			data = data[index+len(i.seq):]
+		}
+
+		for size := len(i.seq) - 1; !final && size > 0; size-- {
+			if bytes.HasSuffix(data, i.seq[:size]) {
+				keep = size
+				break
+			}
+		}
+		i.out = append(i.out, data[:len(data)-keep]...)
+	} else {
+		whole := len(data) - len(data)%i.unit
+		for offset := 0; offset < whole; offset += i.unit {
+			if !bytes.Equal(data[offset:offset+i.unit], i.seq) {
+				i.out = append(i.out, data[offset:offset+i.unit]...)
+			}
+		}
+
+		keep = len(data) - whole
+		if final {
+			i.out = append(i.out, data[whole:]...)
+			keep = 0
+		}
+	}
+
+	i.pending = append([]byte(nil), data[len(data)-keep:]...)
+}
@@ -0,0 +1,78 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFindInteriorBOMs(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   []int
+	}{
+		{"empty", nil, []int{}},
+		{"no bom", []byte("hello"), []int{}},
+		{"leading only", []byte("\xEF\xBB\xBFhello"), []int{}},
+		{"utf8", []byte("ab\xEF\xBB\xBFcd\xEF\xBB\xBF"), []int{2, 7}},
+		{"utf8 with leading", []byte("\xEF\xBB\xBFa\xEF\xBB\xBFb"), []int{4}},
+		{"utf16le", []byte{0xFF, 0xFE, 'a', 0x00, 0xFF, 0xFE, 'b', 0x00}, []int{4}},
+		{"utf16le not aligned", []byte{0xFF, 0xFE, 'a', 0xFF, 0xFE, 0x00}, []int{}},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'a'}, []int{4}},
+	}
+
+	for _, test := range tests {
+		if got := FindInteriorBOMs(test.buffer); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNewInteriorBOMReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"empty", nil, nil},
+		{"no bom", []byte("hello"), []byte("hello")},
+		{"utf8", []byte("ab\xEF\xBB\xBFcd\xEF\xBB\xBF"), []byte("abcd")},
+		{"utf8 keeps leading", []byte("\xEF\xBB\xBFa\xEF\xBB\xBFb"), []byte("\xEF\xBB\xBFab")},
+		{"utf8 partial at the end", []byte("ab\xEF\xBB"), []byte("ab\xEF\xBB")},
+		{
+			"utf16le",
+			[]byte{0xFF, 0xFE, 'a', 0x00, 0xFF, 0xFE, 'b', 0x00, 'c'},
+			[]byte{0xFF, 0xFE, 'a', 0x00, 'b', 0x00, 'c'},
+		},
+		{
+			"utf32le",
+			[]byte{0xFF, 0xFE, 0x00, 0x00, 'a', 0, 0, 0, 0xFF, 0xFE, 0x00, 0x00, 'b', 0, 0, 0},
+			[]byte{0xFF, 0xFE, 0x00, 0x00, 'a', 0, 0, 0, 'b', 0, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		readers := map[string]io.Reader{
+			"plain":    bytes.NewReader(test.input),
+			"one byte": iotest.OneByteReader(bytes.NewReader(test.input)),
+			"half":     iotest.HalfReader(bytes.NewReader(test.input)),
+		}
+		for kind, source := range readers {
+			got, err := io.ReadAll(NewInteriorBOMReader(source))
+			if err != nil {
+				t.Errorf("%s/%s: unexpected error: %s", test.name, kind, err)
+				continue
+			}
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("%s/%s: got %v, want %v", test.name, kind, got, test.want)
+			}
+		}
+	}
+
+	if err := iotest.TestReader(NewInteriorBOMReader(bytes.NewReader([]byte("a\xEF\xBB\xBFb"))), []byte("ab")); err != nil {
+		t.Error(err)
+	}
+}
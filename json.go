@@ -0,0 +1,98 @@
+package gobom
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/text/transform"
+)
+
+// DetectJSONEncoding detects the encoding of a JSON document, as described by
+// RFC 4627 section 3.
+//
+// When buffer starts with a UTF-8, UTF-16 or UTF-32 BOM, the BOM decides.
+// Otherwise, since the first two chars of a JSON text are ASCII, the encoding
+// is decided by the pattern of the NUL bytes in the first four bytes:
+//
+//	00 00 00 xx  UTF-32BE
+//	00 xx 00 xx  UTF-16BE
+//	xx 00 00 00  UTF-32LE
+//	xx 00 xx 00  UTF-16LE
+//	xx xx xx xx  UTF-8
+//
+// A buffer that is too short for any of the patterns is considered UTF-8,
+// which is the default encoding of JSON.
+func DetectJSONEncoding(buffer []byte) BOMType {
+	switch bomType := DetectBOMTypeFromBuffer(buffer); bomType {
+	case UTF8, UTF16LE, UTF16BE, UTF32LE, UTF32BE:
+		return bomType
+	}
+
+	return asciiLayout(buffer)
+}
+
+// asciiLayout detects the encoding of content that starts with ASCII chars,
+// by the pattern of the NUL bytes at the beginning of it. Anything that does
+// not fit UTF-16 or UTF-32 is considered UTF-8.
+func asciiLayout(buffer []byte) BOMType {
+	if len(buffer) >= 4 {
+		switch {
+		case buffer[0] == 0 && buffer[1] == 0 && buffer[2] == 0 && buffer[3] != 0:
+			return UTF32BE
+		case buffer[0] == 0 && buffer[1] != 0 && buffer[2] == 0 && buffer[3] != 0:
+			return UTF16BE
+		case buffer[0] != 0 && buffer[1] == 0 && buffer[2] == 0 && buffer[3] == 0:
+			return UTF32LE
+		case buffer[0] != 0 && buffer[1] == 0 && buffer[2] != 0 && buffer[3] == 0:
+			return UTF16LE
+		}
+
+		return UTF8
+	}
+
+	// a JSON text with a single char, such as 1, has only two bytes in UTF-16
+	if len(buffer) >= 2 {
+		switch {
+		case buffer[0] == 0 && buffer[1] != 0:
+			return UTF16BE
+		case buffer[0] != 0 && buffer[1] == 0:
+			return UTF16LE
+		}
+	}
+
+	return UTF8
+}
+
+// layoutReader decodes content that starts with ASCII chars, such as JSON and
+// XML, to UTF-8, by the encoding DetectJSONEncoding detects
+type layoutReader struct {
+	source io.Reader
+	reader io.Reader
+}
+
+// NewJSONDecoder creates a new json.Decoder on top of r, that removes the BOM
+// of r, which encoding/json rejects, and decodes UTF-16 and UTF-32 content to
+// UTF-8. The encoding is detected by DetectJSONEncoding, so UTF-16 and UTF-32
+// content without a BOM, as allowed by RFC 4627, is decoded as well.
+//
+// The detection happens on the first call to Decode, and reads the first 4
+// bytes of r. An error of r while detecting is returned by Decode.
+func NewJSONDecoder(r io.Reader) *json.Decoder {
+	return json.NewDecoder(&layoutReader{source: r})
+}
+
+// Read is an implementation of io.Reader interface
+func (l *layoutReader) Read(buffer []byte) (int, error) {
+	if l.reader == nil {
+		header, err := readHeader(l.source, 4)
+		bomType := DetectJSONEncoding(header)
+		_, size := matchSignatureLen(signatures, header)
+
+		l.reader = &prefixReader{prefix: header[size:], err: err, reader: l.source}
+		if decoder := decoderOf(bomType); decoder != nil {
+			l.reader = transform.NewReader(l.reader, decoder)
+		}
+	}
+
+	return l.reader.Read(buffer)
+}
@@ -0,0 +1,83 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectJSONEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   BOMType
+	}{
+		{"empty", nil, UTF8},
+		{"utf8", []byte(`{"a":1}`), UTF8},
+		{"utf8 bom", []byte("\xEF\xBB\xBF{}"), UTF8},
+		{"utf16be", []byte{0x00, '{', 0x00, '}'}, UTF16BE},
+		{"utf16le", []byte{'{', 0x00, '}', 0x00}, UTF16LE},
+		{"utf32be", []byte{0x00, 0x00, 0x00, '['}, UTF32BE},
+		{"utf32le", []byte{'[', 0x00, 0x00, 0x00}, UTF32LE},
+		{"utf16le bom", []byte{0xFF, 0xFE, '{', 0x00}, UTF16LE},
+		{"utf32le bom", []byte{0xFF, 0xFE, 0x00, 0x00, '[', 0x00, 0x00, 0x00}, UTF32LE},
+		{"short utf16be", []byte{0x00, '1'}, UTF16BE},
+		{"short utf16le", []byte{'1', 0x00}, UTF16LE},
+		{"short utf8", []byte("1"), UTF8},
+	}
+
+	for _, test := range tests {
+		if got := DetectJSONEncoding(test.buffer); got != test.want {
+			t.Errorf("%s: DetectJSONEncoding(%v) = %s, want %s", test.name, test.buffer, got, test.want)
+		}
+	}
+}
+
+func TestNewJSONDecoder(t *testing.T) {
+	document := `{"name":"café"} {"name":"x"}`
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"utf8", []byte(document)},
+		{"utf8 bom", append([]byte{0xEF, 0xBB, 0xBF}, document...)},
+		{"utf16le bom", mustEncode(t, document, UTF16LE)},
+		{"utf16be", mustEncode(t, document, UTF16BE)[2:]},
+		{"utf32le bom", mustEncode(t, document, UTF32LE)},
+		{"utf32be", mustEncode(t, document, UTF32BE)[4:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewJSONDecoder(iotest.OneByteReader(bytes.NewReader(tt.content)))
+
+			var got []string
+			for {
+				var value struct{ Name string }
+				if err := decoder.Decode(&value); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				got = append(got, value.Name)
+			}
+
+			if len(got) != 2 || got[0] != "café" || got[1] != "x" {
+				t.Errorf("got %q, want [café x]", got)
+			}
+		})
+	}
+}
+
+func TestNewJSONDecoderShort(t *testing.T) {
+	var value int
+	if err := NewJSONDecoder(bytes.NewReader([]byte("7"))).Decode(&value); err != nil || value != 7 {
+		t.Errorf("Decode() = %d, %v, want 7", value, err)
+	}
+
+	if err := NewJSONDecoder(bytes.NewReader(nil)).Decode(&value); err != io.EOF {
+		t.Errorf("Decode() error = %v, want %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,73 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+)
+
+// scanJSONLines reads the records of JSON Lines (NDJSON) content from src, and
+// passes each one of them to emit, without the BOMs at the beginning of the
+// content and of the lines, and without blank lines
+func scanJSONLines(src io.Reader, opts []LineReaderOption, emit func(record []byte) error) error {
+	lines := NewLineReader(src, opts...)
+	lines.scanner.Split(ScanLinesNoBOM)
+
+	for lines.scanner.Scan() {
+		if len(lines.scanner.Bytes()) > lines.maxLen {
+			return bufio.ErrTooLong
+		}
+
+		record := bytes.TrimSpace(lines.scanner.Bytes())
+		if len(record) == 0 {
+			continue
+		}
+		if err := emit(record); err != nil {
+			return err
+		}
+	}
+
+	return lines.scanner.Err()
+}
+
+// CopyJSONLines copies the records of JSON Lines (NDJSON) content from src to
+// dst, one per line, and returns the number of records it copied. The BOM at
+// the beginning of src is removed, and so is a UTF-8 BOM at the beginning of
+// any line, such as those that are left when files of several exports are
+// joined. UTF-16 and UTF-32 content is decoded to UTF-8, as NewUTF8Reader does.
+//
+// Blank lines are dropped, and the lines of dst end with LF. The maximum
+// length of a record can be set by WithMaxLineLength.
+func CopyJSONLines(dst io.Writer, src io.Reader, opts ...LineReaderOption) (int, error) {
+	records := 0
+	var line []byte
+	err := scanJSONLines(src, opts, func(record []byte) error {
+		line = append(append(line[:0], record...), '\n')
+		if _, err := dst.Write(line); err != nil {
+			return err
+		}
+		records++
+		return nil
+	})
+
+	return records, err
+}
+
+// SendJSONLines sends the records of JSON Lines (NDJSON) content from src to
+// records, cleaned as CopyJSONLines does, until the end of src, and returns
+// nil then. Each record is a new slice, which the receiver owns. records is
+// not closed.
+//
+// When ctx is done before a record was received, the error of ctx is
+// returned.
+func SendJSONLines(ctx context.Context, src io.Reader, records chan<- []byte, opts ...LineReaderOption) error {
+	return scanJSONLines(src, opts, func(record []byte) error {
+		select {
+		case records <- append([]byte(nil), record...):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
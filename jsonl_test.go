@@ -0,0 +1,75 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCopyJSONLines(t *testing.T) {
+	want := "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n"
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"clean", []byte("{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n")},
+		{"stream bom", []byte("\xEF\xBB\xBF{\"a\":1}\n{\"a\":2}\n{\"a\":3}")},
+		{"record boms", []byte("\xEF\xBB\xBF{\"a\":1}\r\n\xEF\xBB\xBF{\"a\":2}\n\n\xEF\xBB\xBF{\"a\":3}\n")},
+		{"utf16le", mustEncode(t, "{\"a\":1}\n\uFEFF{\"a\":2}\n{\"a\":3}\n", UTF16LE)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			n, err := CopyJSONLines(&out, iotest.OneByteReader(bytes.NewReader(tt.content)))
+			if n != 3 || err != nil {
+				t.Errorf("CopyJSONLines() = %d, %v, want 3", n, err)
+			}
+			if out.String() != want {
+				t.Errorf("got %q, want %q", out.String(), want)
+			}
+		})
+	}
+}
+
+func TestCopyJSONLinesTooLong(t *testing.T) {
+	var out bytes.Buffer
+	for _, max := range []int{4, 6} {
+		_, err := CopyJSONLines(&out, strings.NewReader("{\"a\":1}\n"), WithMaxLineLength(max))
+		if err != bufio.ErrTooLong {
+			t.Errorf("CopyJSONLines() with %d error = %v, want %v", max, err, bufio.ErrTooLong)
+		}
+	}
+
+	out.Reset()
+	if n, err := CopyJSONLines(&out, strings.NewReader("{\"a\":1}\r\n"), WithMaxLineLength(7)); n != 1 || err != nil {
+		t.Errorf("CopyJSONLines() = %d, %v, want 1", n, err)
+	}
+}
+
+func TestSendJSONLines(t *testing.T) {
+	records := make(chan []byte, 3)
+	err := SendJSONLines(context.Background(), strings.NewReader("\xEF\xBB\xBF1\n\xEF\xBB\xBF2\n3"), records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(records)
+
+	var got []string
+	for record := range records {
+		got = append(got, string(record))
+	}
+	if strings.Join(got, ",") != "1,2,3" {
+		t.Errorf("got %q, want [1 2 3]", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SendJSONLines(ctx, strings.NewReader("1\n"), make(chan []byte)); err != context.Canceled {
+		t.Errorf("SendJSONLines() error = %v, want %v", err, context.Canceled)
+	}
+}
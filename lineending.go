@@ -0,0 +1,153 @@
+package gobom
+
+import "io"
+
+// LineEnding is the style of the line endings of text
+type LineEnding uint8
+
+// Enumeration of the line ending styles
+const (
+	// NoLineEnding is reported for text without line endings
+	NoLineEnding LineEnding = iota
+	// LF is the Unix line ending (\n)
+	LF
+	// CRLF is the Windows line ending (\r\n)
+	CRLF
+	// CR is the classic Mac OS line ending (\r)
+	CR
+)
+
+// String returns the name of l
+func (l LineEnding) String() string {
+	switch l {
+	case LF:
+		return "LF"
+	case CRLF:
+		return "CRLF"
+	case CR:
+		return "CR"
+	}
+
+	return "None"
+}
+
+// LineEndingCounts holds the number of line endings of each style
+type LineEndingCounts struct {
+	LF   int
+	CRLF int
+	CR   int
+}
+
+// Dominant returns the most common line ending style. On a tie LF is preferred
+// over CRLF, and CRLF over CR.
+func (c LineEndingCounts) Dominant() LineEnding {
+	switch {
+	case c.LF == 0 && c.CRLF == 0 && c.CR == 0:
+		return NoLineEnding
+	case c.LF >= c.CRLF && c.LF >= c.CR:
+		return LF
+	case c.CRLF >= c.CR:
+		return CRLF
+	}
+
+	return CR
+}
+
+// Mixed checks if more than one line ending style was found
+func (c LineEndingCounts) Mixed() bool {
+	styles := 0
+	for _, count := range []int{c.LF, c.CRLF, c.CR} {
+		if count > 0 {
+			styles++
+		}
+	}
+
+	return styles > 1
+}
+
+// TextInspection is the result of inspecting text
+type TextInspection struct {
+	// Type is the BOM type of the text
+	Type BOMType
+	// LineEnding is the dominant line ending style of the text
+	LineEnding LineEnding
+	// Counts holds the number of line endings of each style
+	Counts LineEndingCounts
+	// Truncated is true when the text was read from a reader, and was not
+	// examined to its end because of the sniff limit
+	Truncated bool
+}
+
+// countLineEndings counts the line endings of content of t, by the code units
+// of t
+func countLineEndings(content []byte, t BOMType) LineEndingCounts {
+	_, unit := encodedBOMRune(t)
+	order, _ := t.ByteOrder()
+
+	var counts LineEndingCounts
+	cr := false
+	for offset := 0; offset+unit <= len(content); offset += unit {
+		var value uint32
+		switch unit {
+		case 1:
+			value = uint32(content[offset])
+		case 2:
+			value = uint32(order.Uint16(content[offset:]))
+		default:
+			value = order.Uint32(content[offset:])
+		}
+
+		if cr {
+			cr = false
+			if value == '\n' {
+				counts.CRLF++
+				continue
+			}
+			counts.CR++
+		}
+
+		switch value {
+		case '\r':
+			cr = true
+		case '\n':
+			counts.LF++
+		}
+	}
+
+	if cr {
+		counts.CR++
+	}
+
+	return counts
+}
+
+// InspectText detects the BOM type of buffer, and counts its line endings in
+// the same pass. The line endings of UTF-16 and UTF-32 text are counted by
+// its code units, and any other text is counted by its bytes.
+func InspectText(buffer []byte) TextInspection {
+	bomType, size := matchSignatureLen(signatures, buffer)
+	counts := countLineEndings(buffer[size:], bomType)
+
+	return TextInspection{
+		Type:       bomType,
+		LineEnding: counts.Dominant(),
+		Counts:     counts,
+	}
+}
+
+// InspectTextReader is InspectText for the content of r. It reads the BOM of r
+// and up to 8 KiB of content after it, or the limit that was set by
+// WithSniffLimit, and Truncated tells if the content is longer than that.
+func InspectTextReader(r io.Reader, opts ...HeuristicOption) (TextInspection, error) {
+	options := newHeuristicOptions(opts)
+
+	buffer, truncated, err := sniff(r, options.sniffLimit)
+	if err != nil {
+		return TextInspection{}, err
+	}
+
+	inspection := InspectText(buffer)
+	inspection.Truncated = truncated
+
+	return inspection, nil
+}
@@ -0,0 +1,87 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLineEndingCounts(t *testing.T) {
+	tests := []struct {
+		counts    LineEndingCounts
+		wantStyle LineEnding
+		wantMixed bool
+	}{
+		{LineEndingCounts{}, NoLineEnding, false},
+		{LineEndingCounts{LF: 2}, LF, false},
+		{LineEndingCounts{CRLF: 2, LF: 1}, CRLF, true},
+		{LineEndingCounts{CR: 3, CRLF: 1}, CR, true},
+		{LineEndingCounts{LF: 1, CRLF: 1}, LF, true},
+	}
+
+	for _, test := range tests {
+		if got := test.counts.Dominant(); got != test.wantStyle {
+			t.Errorf("%+v.Dominant() = %s, want %s", test.counts, got, test.wantStyle)
+		}
+		if got := test.counts.Mixed(); got != test.wantMixed {
+			t.Errorf("%+v.Mixed() = %t, want %t", test.counts, got, test.wantMixed)
+		}
+	}
+}
+
+func TestInspectText(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   TextInspection
+	}{
+		{"empty", nil, TextInspection{Type: Unknown}},
+		{"lf", []byte("a\nb\n"), TextInspection{Type: Unknown, LineEnding: LF, Counts: LineEndingCounts{LF: 2}}},
+		{"utf8 crlf", []byte("\xEF\xBB\xBFa\r\nb\r\nc\n"), TextInspection{Type: UTF8, LineEnding: CRLF, Counts: LineEndingCounts{LF: 1, CRLF: 2}}},
+		{"trailing cr", []byte("a\rb\r"), TextInspection{Type: Unknown, LineEnding: CR, Counts: LineEndingCounts{CR: 2}}},
+		{"utf16le crlf", append([]byte{0xFF, 0xFE}, utf16le("a\r\nb\r\n")...), TextInspection{Type: UTF16LE, LineEnding: CRLF, Counts: LineEndingCounts{CRLF: 2}}},
+		{"utf16le no false lf", []byte{0xFF, 0xFE, 0x0A, 0x01, 0x0D, 0x02}, TextInspection{Type: UTF16LE}},
+		{"utf32be lf", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'a', 0x00, 0x00, 0x00, '\n'}, TextInspection{Type: UTF32BE, LineEnding: LF, Counts: LineEndingCounts{LF: 1}}},
+	}
+
+	for _, test := range tests {
+		if got := InspectText(test.buffer); got != test.want {
+			t.Errorf("%s: InspectText() = %+v, want %+v", test.name, got, test.want)
+		}
+
+		got, err := InspectTextReader(iotest.OneByteReader(bytes.NewReader(test.buffer)))
+		if err != nil || got != test.want {
+			t.Errorf("%s: InspectTextReader() = %+v, %v, want %+v", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestInspectTextReaderError(t *testing.T) {
+	errRead := errors.New("read error")
+
+	if _, err := InspectTextReader(iotest.ErrReader(errRead)); err != errRead {
+		t.Errorf("InspectTextReader() error = %v, want %v", err, errRead)
+	}
+
+	r := iotest.TimeoutReader(bytes.NewReader([]byte("abcdef\n")))
+	if _, err := InspectTextReader(r); err != iotest.ErrTimeout {
+		t.Errorf("InspectTextReader() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestInspectTextReaderSniffLimit(t *testing.T) {
+	content := []byte("\xEF\xBB\xBFa\nb\r\nc\r\n")
+
+	got, err := InspectTextReader(bytes.NewReader(content), WithSniffLimit(4))
+	want := TextInspection{Type: UTF8, LineEnding: LF, Counts: LineEndingCounts{LF: 1, CR: 1}, Truncated: true}
+	if err != nil || got != want {
+		t.Errorf("InspectTextReader(4) = %+v, %v, want %+v", got, err, want)
+	}
+
+	got, err = InspectTextReader(bytes.NewReader(content), WithSniffLimit(len(content)-3))
+	want = TextInspection{Type: UTF8, LineEnding: CRLF, Counts: LineEndingCounts{LF: 1, CRLF: 2}}
+	if err != nil || got != want {
+		t.Errorf("InspectTextReader(all) = %+v, %v, want %+v", got, err, want)
+	}
+}
@@ -0,0 +1,74 @@
+package gobom
+
+import (
+	"bufio"
+	"io"
+)
+
+// LineReader reads text line by line, as strings, whatever its encoding is:
+// the BOM is removed, and UTF-16 and UTF-32 content is decoded to UTF-8 as
+// NewUTF8Reader does. It covers the common case of reading a text file that
+// was saved by a Windows tool.
+type LineReader struct {
+	scanner *bufio.Scanner
+	maxLen  int
+	err     error
+}
+
+// LineReaderOption configures a LineReader
+type LineReaderOption func(*LineReader)
+
+// WithMaxLineLength sets the maximum length of a line in bytes, after it was
+// decoded to UTF-8, and without its line ending. The default is
+// bufio.MaxScanTokenSize (64 KiB), and a non-positive n keeps it.
+func WithMaxLineLength(n int) LineReaderOption {
+	return func(l *LineReader) {
+		if n > 0 {
+			l.maxLen = n
+		}
+	}
+}
+
+// NewLineReader creates a new LineReader on top of r, and configures it with
+// opts.
+func NewLineReader(r io.Reader, opts ...LineReaderOption) *LineReader {
+	l := &LineReader{maxLen: bufio.MaxScanTokenSize}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	// the scanner holds the line with its line ending, which takes up to 2
+	// bytes (CRLF)
+	l.scanner = bufio.NewScanner(NewUTF8Reader(r))
+	size := 4096
+	if l.maxLen+2 < size {
+		size = l.maxLen + 2
+	}
+	l.scanner.Buffer(make([]byte, 0, size), l.maxLen+2)
+
+	return l
+}
+
+// ReadLine returns the next line, without its line ending (LF or CRLF). At the
+// end of the content it returns io.EOF, and for a line that is longer than
+// the maximum length it returns bufio.ErrTooLong. Any other error is the error
+// of the wrapped reader.
+func (l *LineReader) ReadLine() (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+
+	if l.scanner.Scan() {
+		if len(l.scanner.Bytes()) > l.maxLen {
+			l.err = bufio.ErrTooLong
+			return "", l.err
+		}
+		return l.scanner.Text(), nil
+	}
+
+	if err := l.scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", io.EOF
+}
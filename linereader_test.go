@@ -0,0 +1,86 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLineReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []string
+	}{
+		{"empty", []byte{}, nil},
+		{"no bom", []byte("a\nb"), []string{"a", "b"}},
+		{"utf8 crlf", []byte("\xEF\xBB\xBFname\r\nnaïve\r\n"), []string{"name", "naïve"}},
+		{"utf16le crlf", append([]byte{0xFF, 0xFE}, utf16le("a\r\nb\r\n")...), []string{"a", "b"}},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x20, 0xAC, 0x00, 0x00, 0x00, '\n', 0x00, 0x00, 0x00, 'x'}, []string{"€", "x"}},
+	}
+
+	for _, test := range tests {
+		reader := NewLineReader(iotest.HalfReader(bytes.NewReader(test.input)))
+
+		var got []string
+		for {
+			line, err := reader.ReadLine()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			got = append(got, line)
+		}
+
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLineReaderMaxLineLength(t *testing.T) {
+	reader := NewLineReader(bytes.NewReader([]byte("short\nmuch longer\n")), WithMaxLineLength(8))
+
+	if line, err := reader.ReadLine(); line != "short" || err != nil {
+		t.Errorf("ReadLine() = %q, %v, want %q", line, err, "short")
+	}
+	if _, err := reader.ReadLine(); err != bufio.ErrTooLong {
+		t.Errorf("ReadLine() error = %v, want %v", err, bufio.ErrTooLong)
+	}
+
+	tests := []struct {
+		name    string
+		max     int
+		content string
+		want    string
+		wantErr error
+	}{
+		{"exact lf", 5, "abcde\n", "abcde", nil},
+		{"exact crlf", 5, "abcde\r\n", "abcde", nil},
+		{"exact last line", 5, "abcde", "abcde", nil},
+		{"one more", 5, "abcdef\n", "", bufio.ErrTooLong},
+		{"two more", 5, "abcdefg\n", "", bufio.ErrTooLong},
+		{"one more last line", 5, "abcdef", "", bufio.ErrTooLong},
+		{"zero keeps default", 0, "abcdef\n", "abcdef", nil},
+		{"negative keeps default", -1, "abcdef\n", "abcdef", nil},
+	}
+	for _, tt := range tests {
+		line, err := NewLineReader(bytes.NewReader([]byte(tt.content)), WithMaxLineLength(tt.max)).ReadLine()
+		if line != tt.want || err != tt.wantErr {
+			t.Errorf("%s: ReadLine() = %q, %v, want %q, %v", tt.name, line, err, tt.want, tt.wantErr)
+		}
+	}
+
+	reader = NewLineReader(iotest.TimeoutReader(bytes.NewReader([]byte("\xEF\xBB\xBFa\nb"))))
+	if line, err := reader.ReadLine(); line != "a" || err != nil {
+		t.Errorf("ReadLine() = %q, %v, want %q", line, err, "a")
+	}
+	if _, err := reader.ReadLine(); err != iotest.ErrTimeout {
+		t.Errorf("ReadLine() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
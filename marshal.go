@@ -0,0 +1,62 @@
+package gobom
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalText is an implementation of encoding.TextMarshaler interface, and
+// returns the name of the BOM type, such as "UTF-16LE".
+func (t BOMType) MarshalText() ([]byte, error) {
+	name, ok := bomTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("gobom: cannot marshal unknown BOM type %d", uint8(t))
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText is an implementation of encoding.TextUnmarshaler interface.
+// It accepts any name that ParseBOMType accepts.
+func (t *BOMType) UnmarshalText(text []byte) error {
+	bomType, err := ParseBOMType(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = bomType
+	return nil
+}
+
+// MarshalJSON is an implementation of json.Marshaler interface, and returns the
+// name of the BOM type as a JSON string.
+func (t BOMType) MarshalJSON() ([]byte, error) {
+	text, err := t.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON is an implementation of json.Unmarshaler interface.
+// It accepts the name of the BOM type as a JSON string, and also a JSON number,
+// which is how BOMType was serialized before.
+func (t *BOMType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return t.UnmarshalText([]byte(name))
+	}
+
+	var value uint8
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("gobom: cannot unmarshal %s into BOMType", data)
+	}
+
+	if _, ok := bomTypeNames[BOMType(value)]; !ok {
+		return fmt.Errorf("gobom: unknown BOM type %d", value)
+	}
+
+	*t = BOMType(value)
+	return nil
+}
@@ -0,0 +1,61 @@
+package gobom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBOMTypeJSON(t *testing.T) {
+	type result struct {
+		Type BOMType `json:"type"`
+	}
+
+	for bomType := range bomTypeNames {
+		data, err := json.Marshal(result{Type: bomType})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", bomType, err)
+			continue
+		}
+
+		want := `{"type":"` + bomType.String() + `"}`
+		if string(data) != want {
+			t.Errorf("got %s, want %s", data, want)
+		}
+
+		var got result
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("%s: unexpected error: %s", bomType, err)
+			continue
+		}
+		if got.Type != bomType {
+			t.Errorf("got %s, want %s", got.Type, bomType)
+		}
+	}
+
+	var got result
+	if err := json.Unmarshal([]byte(`{"type":2}`), &got); err != nil || got.Type != UTF16LE {
+		t.Errorf("got %s, %v, want %s", got.Type, err, UTF16LE)
+	}
+
+	for _, data := range []string{`{"type":"latin1"}`, `{"type":200}`, `{"type":true}`} {
+		if err := json.Unmarshal([]byte(data), &got); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+
+	if _, err := json.Marshal(BOMType(200)); err == nil {
+		t.Error("expected an error for an unknown BOM type")
+	}
+}
+
+func TestBOMTypeText(t *testing.T) {
+	text, err := UTF32BE.MarshalText()
+	if err != nil || string(text) != "UTF-32BE" {
+		t.Errorf("MarshalText() = %s, %v", text, err)
+	}
+
+	var bomType BOMType
+	if err := bomType.UnmarshalText([]byte("utf-16be")); err != nil || bomType != UTF16BE {
+		t.Errorf("UnmarshalText() = %s, %v", bomType, err)
+	}
+}
@@ -0,0 +1,153 @@
+package gobom
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// Mojibake BOMs are BOMs that were decoded as Latin-1 (or windows-1252), and
+// then encoded again as UTF-8, so the content starts with the BOM chars as
+// text, such as "ï»¿" instead of the UTF-8 BOM.
+var (
+	UTF8MojibakeBom    = []byte{0xC3, 0xAF, 0xC2, 0xBB, 0xC2, 0xBF}
+	UTF16LEMojibakeBom = []byte{0xC3, 0xBF, 0xC3, 0xBE}
+	UTF16BEMojibakeBom = []byte{0xC3, 0xBE, 0xC3, 0xBF}
+	UTF32LEMojibakeBom = []byte{0xC3, 0xBF, 0xC3, 0xBE, 0x00, 0x00}
+	UTF32BEMojibakeBom = []byte{0x00, 0x00, 0xC3, 0xBE, 0xC3, 0xBF}
+)
+
+// mojibakeSignatures holds the mojibake BOMs, ordered from the longest to the
+// shortest one, as signatures does
+var mojibakeSignatures = []signature{
+	{UTF8, UTF8MojibakeBom},
+	{UTF32LE, UTF32LEMojibakeBom},
+	{UTF32BE, UTF32BEMojibakeBom},
+	{UTF16LE, UTF16LEMojibakeBom},
+	{UTF16BE, UTF16BEMojibakeBom},
+}
+
+// DetectMojibakeBOM detects if buffer starts with a mojibake BOM, which is a
+// sign that the content was encoded twice, and returns the type of the
+// original BOM. For example "ï»¿" is reported as UTF8, and "ÿþ" as UTF16LE.
+// If there is no mojibake BOM, it returns Unknown.
+func DetectMojibakeBOM(buffer []byte) BOMType {
+	return matchSignature(mojibakeSignatures, buffer)
+}
+
+// HasMojibakeBOM returns true if buffer starts with a mojibake BOM.
+func HasMojibakeBOM(buffer []byte) bool {
+	return DetectMojibakeBOM(buffer) != Unknown
+}
+
+// windows1252Bytes maps the chars that windows-1252 places at 0x80 - 0x9F back
+// to their bytes
+var windows1252Bytes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// singleByte returns the Latin-1 or windows-1252 byte that r was decoded from
+func singleByte(r rune) (byte, bool) {
+	if r >= 0 && r <= 0xFF {
+		return byte(r), true
+	}
+
+	b, ok := windows1252Bytes[r]
+	return b, ok
+}
+
+// RepairMojibake removes a mojibake BOM from the beginning of buffer, and
+// returns the content without it.
+//
+// When reverse is true, the double encoding of the rest of the content is
+// reversed as well: every char is encoded back to the Latin-1 or windows-1252
+// byte it was decoded from. If the content was not double encoded, or the
+// result is not valid UTF-8, ErrNotDoubleEncoded is returned.
+func RepairMojibake(buffer []byte, reverse bool) ([]byte, error) {
+	_, size := matchSignatureLen(mojibakeSignatures, buffer)
+	buffer = buffer[size:]
+	if !reverse {
+		return buffer, nil
+	}
+
+	repaired := make([]byte, 0, len(buffer))
+	for i := 0; i < len(buffer); {
+		r, size := utf8.DecodeRune(buffer[i:])
+		b, ok := singleByte(r)
+		if !ok || (r == utf8.RuneError && size <= 1) {
+			return nil, ErrNotDoubleEncoded
+		}
+
+		repaired = append(repaired, b)
+		i += size
+	}
+
+	if !utf8.Valid(repaired) {
+		return nil, ErrNotDoubleEncoded
+	}
+
+	return repaired, nil
+}
+
+// maxMojibakeBOMLen is the size of the longest mojibake BOM
+const maxMojibakeBOMLen = 6
+
+// mojibakeReader removes a mojibake BOM from the beginning of a reader, and
+// reverses the double encoding of the rest of it when needed
+type mojibakeReader struct {
+	reader  *bufio.Reader
+	reverse bool
+	started bool
+}
+
+// NewMojibakeReader returns a reader that removes a mojibake BOM from the
+// beginning of r. When reverse is true, the double encoding of the rest of the
+// content is reversed as well, and Read returns ErrNotDoubleEncoded when it
+// finds a char that cannot be encoded back, as RepairMojibake does. Unlike
+// RepairMojibake, the result is not validated to be UTF-8.
+func NewMojibakeReader(r io.Reader, reverse bool) io.Reader {
+	return &mojibakeReader{reader: bufio.NewReader(r), reverse: reverse}
+}
+
+// Read is an implementation of io.Reader interface
+func (m *mojibakeReader) Read(buffer []byte) (int, error) {
+	if !m.started {
+		m.started = true
+
+		// an error here is returned by the reads that follow
+		head, _ := m.reader.Peek(maxMojibakeBOMLen)
+		if _, size := matchSignatureLen(mojibakeSignatures, head); size > 0 {
+			m.reader.Discard(size)
+		}
+	}
+
+	if !m.reverse {
+		return m.reader.Read(buffer)
+	}
+
+	n := 0
+	for n < len(buffer) {
+		// do not block for more chars when some were already read
+		if n > 0 && m.reader.Buffered() == 0 {
+			break
+		}
+
+		r, size, err := m.reader.ReadRune()
+		if err != nil {
+			return n, err
+		}
+
+		b, ok := singleByte(r)
+		if !ok || (r == utf8.RuneError && size <= 1) {
+			return n, ErrNotDoubleEncoded
+		}
+
+		buffer[n] = b
+		n++
+	}
+
+	return n, nil
+}
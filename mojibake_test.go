@@ -0,0 +1,105 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+func TestDetectMojibakeBOM(t *testing.T) {
+	tests := []struct {
+		buffer string
+		want   BOMType
+	}{
+		{"", Unknown},
+		{"hello", Unknown},
+		{"\xEF\xBB\xBFhello", Unknown},
+		{"ï»¿hello", UTF8},
+		{"ï»", Unknown},
+		{"ÿþh\x00", UTF16LE},
+		{"þÿ\x00h", UTF16BE},
+		{"ÿþ\x00\x00h\x00\x00\x00", UTF32LE},
+		{"\x00\x00þÿ\x00\x00\x00h", UTF32BE},
+	}
+
+	for _, test := range tests {
+		if got := DetectMojibakeBOM([]byte(test.buffer)); got != test.want {
+			t.Errorf("DetectMojibakeBOM(%q) = %s, want %s", test.buffer, got, test.want)
+		}
+		if got := HasMojibakeBOM([]byte(test.buffer)); got != (test.want != Unknown) {
+			t.Errorf("HasMojibakeBOM(%q) = %t", test.buffer, got)
+		}
+	}
+}
+
+// doubleEncode decodes s as windows-1252 and encodes it again as UTF-8
+func doubleEncode(s string) []byte {
+	chars := map[byte]rune{}
+	for r, b := range windows1252Bytes {
+		chars[b] = r
+	}
+
+	var buffer []byte
+	for i := 0; i < len(s); i++ {
+		r, ok := chars[s[i]]
+		if !ok {
+			r = rune(s[i])
+		}
+		buffer = utf8.AppendRune(buffer, r)
+	}
+	return buffer
+}
+
+func TestRepairMojibake(t *testing.T) {
+	original := "\xEF\xBB\xBFcafé “crème” €5"
+	damaged := doubleEncode(original)
+
+	got, err := RepairMojibake(damaged, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := doubleEncode("café “crème” €5"); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = RepairMojibake(damaged, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "café “crème” €5"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := RepairMojibake([]byte("ï»¿שלום"), true); err != ErrNotDoubleEncoded {
+		t.Errorf("got %v, want %v", err, ErrNotDoubleEncoded)
+	}
+	if _, err := RepairMojibake([]byte("ï»¿é"), true); err != ErrNotDoubleEncoded {
+		t.Errorf("got %v, want %v", err, ErrNotDoubleEncoded)
+	}
+}
+
+func TestNewMojibakeReader(t *testing.T) {
+	damaged := doubleEncode("\xEF\xBB\xBFcafé “crème” €5")
+
+	for _, reverse := range []bool{false, true} {
+		want, err := RepairMojibake(damaged, reverse)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		got, err := io.ReadAll(NewMojibakeReader(iotest.OneByteReader(bytes.NewReader(damaged)), reverse))
+		if err != nil {
+			t.Fatalf("reverse %t: unexpected error: %s", reverse, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("reverse %t: got %q, want %q", reverse, got, want)
+		}
+	}
+
+	_, err := io.ReadAll(NewMojibakeReader(bytes.NewReader([]byte("ï»¿שלום")), true))
+	if err != ErrNotDoubleEncoded {
+		t.Errorf("got %v, want %v", err, ErrNotDoubleEncoded)
+	}
+}
@@ -0,0 +1,24 @@
+package gobom
+
+// MustDetectFile is DetectFile that panics if the file cannot be read. It is
+// meant for scripts and tests.
+func MustDetectFile(path string) BOMType {
+	bomType, err := DetectFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return bomType
+}
+
+// MustTrimBOM returns a sub slice of b that starts after the BOM, and panics
+// with ErrNoBOM if b does not start with a BOM. It is meant for scripts and
+// tests that expect a BOM to be there.
+func MustTrimBOM(b []byte) []byte {
+	trimmed, bomType := TrimBOM(b)
+	if bomType == Unknown {
+		panic(ErrNoBOM)
+	}
+
+	return trimmed
+}
@@ -0,0 +1,40 @@
+package gobom
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMustDetectFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bom.txt")
+	if err := os.WriteFile(path, []byte{0xEF, 0xBB, 0xBF, 'a'}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := MustDetectFile(path); got != UTF8 {
+		t.Errorf("MustDetectFile() = %s, want %s", got, UTF8)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing file")
+		}
+	}()
+	MustDetectFile(filepath.Join(t.TempDir(), "missing.txt"))
+}
+
+func TestMustTrimBOM(t *testing.T) {
+	got := MustTrimBOM([]byte{0xFE, 0xFF, 0x00, 'a'})
+	if want := []byte{0x00, 'a'}; !bytes.Equal(got, want) {
+		t.Errorf("MustTrimBOM() = %v, want %v", got, want)
+	}
+
+	defer func() {
+		if err := recover(); err != ErrNoBOM {
+			t.Errorf("got panic %v, want %v", err, ErrNoBOM)
+		}
+	}()
+	MustTrimBOM([]byte("hello"))
+}
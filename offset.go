@@ -0,0 +1,33 @@
+package gobom
+
+import "bytes"
+
+// DetectBOMTypeAt detects the BOM type of buffer starting at offset, for
+// content that has a known prefix before the BOM.
+// If offset is out of the range of buffer, it returns Unknown.
+func DetectBOMTypeAt(buffer []byte, offset int) BOMType {
+	if offset < 0 || offset > len(buffer) {
+		return Unknown
+	}
+
+	return DetectBOMTypeFromBuffer(buffer[offset:])
+}
+
+// DetectBOMTypeAfterShebang detects the BOM type of a script. When buffer
+// starts with a shebang line ("#!..."), the detection is made on the first
+// byte after that line, otherwise it is made on the beginning of buffer.
+//
+// It also returns the offset the detection was made at, so the BOM can be
+// removed by the caller.
+func DetectBOMTypeAfterShebang(buffer []byte) (BOMType, int) {
+	offset := 0
+	if bytes.HasPrefix(buffer, []byte("#!")) {
+		i := bytes.IndexByte(buffer, '\n')
+		if i < 0 {
+			return Unknown, len(buffer)
+		}
+		offset = i + 1
+	}
+
+	return DetectBOMTypeAt(buffer, offset), offset
+}
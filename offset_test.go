@@ -0,0 +1,47 @@
+package gobom
+
+import "testing"
+
+func TestDetectBOMTypeAt(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		offset int
+		want   BOMType
+	}{
+		{nil, 0, Unknown},
+		{[]byte("ab"), -1, Unknown},
+		{[]byte("ab"), 3, Unknown},
+		{[]byte("ab"), 2, Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF}, 0, UTF8},
+		{[]byte{'a', 'b', 0xEF, 0xBB, 0xBF}, 2, UTF8},
+		{[]byte{'a', 0xFF, 0xFE, 0x00, 0x00}, 1, UTF32LE},
+	}
+
+	for _, test := range tests {
+		if got := DetectBOMTypeAt(test.buffer, test.offset); got != test.want {
+			t.Errorf("DetectBOMTypeAt(%v, %d) = %s, want %s", test.buffer, test.offset, got, test.want)
+		}
+	}
+}
+
+func TestDetectBOMTypeAfterShebang(t *testing.T) {
+	tests := []struct {
+		buffer     string
+		want       BOMType
+		wantOffset int
+	}{
+		{"", Unknown, 0},
+		{"\xEF\xBB\xBFecho", UTF8, 0},
+		{"#!/bin/sh\n\xEF\xBB\xBFecho", UTF8, 10},
+		{"#!/bin/sh\necho", Unknown, 10},
+		{"#!/bin/sh", Unknown, 9},
+	}
+
+	for _, test := range tests {
+		got, offset := DetectBOMTypeAfterShebang([]byte(test.buffer))
+		if got != test.want || offset != test.wantOffset {
+			t.Errorf("DetectBOMTypeAfterShebang(%q) = %s, %d, want %s, %d",
+				test.buffer, got, offset, test.want, test.wantOffset)
+		}
+	}
+}
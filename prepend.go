@@ -0,0 +1,29 @@
+package gobom
+
+// AppendBOM appends the BOM of t to dst and returns the extended slice, in the
+// same manner as the builtin append. If t is Unknown, dst is returned as is.
+//
+// It is useful for starting a new output buffer, such as AppendBOM(nil, UTF8).
+func AppendBOM(dst []byte, t BOMType) []byte {
+	return append(dst, signatureOf(t)...)
+}
+
+// PrependBOM places the BOM of t in front of the content of dst, and returns
+// the extended slice. If t is Unknown, dst is returned as is.
+//
+// When dst has enough capacity, the content is moved in place, so the
+// underlying array of dst is modified, in the same manner as the builtin
+// append.
+func PrependBOM(dst []byte, t BOMType) []byte {
+	bom := signatureOf(t)
+	if len(bom) == 0 {
+		return dst
+	}
+
+	size := len(dst)
+	dst = append(dst, bom...)
+	copy(dst[len(bom):], dst[:size])
+	copy(dst, bom)
+
+	return dst
+}
@@ -0,0 +1,45 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendBOM(t *testing.T) {
+	if got := AppendBOM(nil, Unknown); len(got) != 0 {
+		t.Errorf("AppendBOM(nil, Unknown) = %v, want empty", got)
+	}
+
+	got := AppendBOM([]byte("a"), UTF8)
+	if want := []byte{'a', 0xEF, 0xBB, 0xBF}; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got = AppendBOM(nil, UTF16LE)
+	got = append(got, 'a', 0x00)
+	if want := []byte{0xFF, 0xFE, 'a', 0x00}; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPrependBOM(t *testing.T) {
+	tests := []struct {
+		dst     []byte
+		bomType BOMType
+		want    []byte
+	}{
+		{nil, Unknown, nil},
+		{[]byte("abc"), Unknown, []byte("abc")},
+		{nil, UTF8, []byte{0xEF, 0xBB, 0xBF}},
+		{[]byte("abc"), UTF8, []byte{0xEF, 0xBB, 0xBF, 'a', 'b', 'c'}},
+		{[]byte{'a', 0x00}, UTF16LE, []byte{0xFF, 0xFE, 'a', 0x00}},
+		{[]byte{'a'}, UTF32BE, []byte{0x00, 0x00, 0xFE, 0xFF, 'a'}},
+		{make([]byte, 2, 10), UTF8, []byte{0xEF, 0xBB, 0xBF, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		if got := PrependBOM(test.dst, test.bomType); !bytes.Equal(got, test.want) {
+			t.Errorf("PrependBOM(%v, %s) = %v, want %v", test.dst, test.bomType, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package gobom
+
+import "io"
+
+// TextProfile is a report about the beginning of the content of a reader
+type TextProfile struct {
+	// Type is the BOM type of the content
+	Type BOMType
+	// Encoding is the most plausible encoding of the content, as ranked by
+	// GuessEncodings, or an empty string when nothing is plausible
+	Encoding string
+	// Confidence is the confidence of Encoding, between 0 and 1
+	Confidence float64
+	// LineEnding is the dominant line ending style of the content
+	LineEnding LineEnding
+	// LineEndings holds the number of line endings of each style
+	LineEndings LineEndingCounts
+	// InteriorBOMs is the number of BOM chars the content holds after its
+	// beginning, as FindInteriorBOMs finds them
+	InteriorBOMs int
+	// Binary is true when the content looks binary, as LooksBinary decides
+	Binary bool
+	// Size is the number of bytes that were examined, including the BOM
+	Size int
+	// BOMSize is the number of bytes the BOM takes
+	BOMSize int
+	// Truncated is true when the content is longer than the sniff limit, and
+	// was not examined to its end
+	Truncated bool
+}
+
+// Profile reads the BOM of r and up to 8 KiB of content after it, or the
+// limit that was set by WithSniffLimit, and reports everything the package can
+// tell about the content in a single pass. Content past the limit is not read,
+// and Truncated tells if there is any.
+//
+// The options are passed to GuessEncodings as well, so WithMinConfidence
+// might leave Encoding empty.
+func Profile(r io.Reader, opts ...HeuristicOption) (TextProfile, error) {
+	options := newHeuristicOptions(opts)
+
+	buffer, truncated, err := sniff(r, options.sniffLimit)
+	if err != nil {
+		return TextProfile{}, err
+	}
+
+	bomType, size := matchSignatureLen(signatures, buffer)
+	inspection := InspectText(buffer)
+	profile := TextProfile{
+		Type:         bomType,
+		LineEnding:   inspection.LineEnding,
+		LineEndings:  inspection.Counts,
+		InteriorBOMs: len(FindInteriorBOMs(buffer)),
+		Binary:       looksBinary(buffer, len(buffer)),
+		Size:         len(buffer),
+		BOMSize:      size,
+		Truncated:    truncated,
+	}
+	if guesses := guessEncodings(buffer, truncated, options); len(guesses) > 0 {
+		profile.Encoding = guesses[0].Encoding
+		profile.Confidence = guesses[0].Confidence
+	}
+
+	return profile, nil
+}
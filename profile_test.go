@@ -0,0 +1,104 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+)
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    TextProfile
+	}{
+		{
+			"empty",
+			nil,
+			TextProfile{},
+		},
+		{
+			"utf8 crlf",
+			[]byte("\xEF\xBB\xBFa\r\nb\xEF\xBB\xBF\r\nc\n"),
+			TextProfile{
+				Type: UTF8, Encoding: UTF8.String(), Confidence: 1,
+				LineEnding: CRLF, LineEndings: LineEndingCounts{LF: 1, CRLF: 2},
+				InteriorBOMs: 1, Size: 14, BOMSize: 3,
+			},
+		},
+		{
+			"utf16le",
+			bytes.Join([][]byte{{0xFF, 0xFE}, utf16le("a\n"), {0xFF, 0xFE}, utf16le("b\n")}, nil),
+			TextProfile{
+				Type: UTF16LE, Encoding: UTF16LE.String(), Confidence: 1,
+				LineEnding: LF, LineEndings: LineEndingCounts{LF: 2},
+				InteriorBOMs: 1, Size: 12, BOMSize: 2,
+			},
+		},
+		{
+			"binary",
+			[]byte("\x7FELF\x02\x01\x01\x00"),
+			TextProfile{Binary: true, Size: 8},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Profile(iotest.HalfReader(bytes.NewReader(test.content)))
+		if err != nil {
+			t.Errorf("%s: Profile() error = %v", test.name, err)
+			continue
+		}
+
+		// the guess is tested by GuessEncodings, only a BOM guess is certain
+		if test.want.Type == Unknown {
+			got.Encoding, got.Confidence = "", 0
+		}
+		if got != test.want {
+			t.Errorf("%s: Profile() = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestProfileError(t *testing.T) {
+	errRead := errors.New("read error")
+
+	if _, err := Profile(iotest.ErrReader(errRead)); err != errRead {
+		t.Errorf("Profile() error = %v, want %v", err, errRead)
+	}
+}
+
+func TestProfileSniffLimit(t *testing.T) {
+	content := append([]byte("\xEF\xBB\xBF"), bytes.Repeat([]byte("line\n"), 10000)...)
+
+	got, err := Profile(bytes.NewReader(content))
+	if err != nil || !got.Truncated || got.Size != 3+sampleSize {
+		t.Errorf("Profile() = %+v, %v, want %d truncated bytes", got, err, 3+sampleSize)
+	}
+
+	got, err = Profile(bytes.NewReader(content), WithSniffLimit(10))
+	want := TextProfile{
+		Type: UTF8, Encoding: UTF8.String(), Confidence: 1,
+		LineEnding: LF, LineEndings: LineEndingCounts{LF: 2},
+		Size: 13, BOMSize: 3, Truncated: true,
+	}
+	if err != nil || got != want {
+		t.Errorf("Profile(10) = %+v, %v, want %+v", got, err, want)
+	}
+
+	// a sequence cut by the limit is not invalid, while one the content ends
+	// with is
+	got, err = Profile(bytes.NewReader([]byte("caf\xC3\xA9")), WithSniffLimit(4))
+	if err != nil || got.Encoding != UTF8.String() {
+		t.Errorf("Profile(cut) = %+v, %v, want %s", got, err, UTF8)
+	}
+	got, err = Profile(bytes.NewReader([]byte("caf\xE9")))
+	if err != nil || got.Encoding != EncodingWindows1252 {
+		t.Errorf("Profile(latin1) = %+v, %v, want %s", got, err, EncodingWindows1252)
+	}
+
+	got, err = Profile(bytes.NewReader(content), WithSniffLimit(len(content)))
+	if err != nil || got.Truncated || got.Size != len(content) || got.LineEndings.LF != 10000 {
+		t.Errorf("Profile(all) = %+v, %v, want %d bytes", got, err, len(content))
+	}
+}
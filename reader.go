@@ -0,0 +1,534 @@
+package gobom
+
+import (
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxBOMLen is the size of the longest BOM that can be detected
+const maxBOMLen = 5
+
+// maxEmptyReads is the number of reads in a row that return no bytes and no
+// error, after which the wrapped reader is considered broken, as bufio does
+const maxEmptyReads = 100
+
+// Reader is an implementation for the io.Reader that removes a BOM from the
+// beginning of the wrapped reader
+type Reader struct {
+	reader    io.Reader
+	buffer    []byte
+	err       error
+	bomType   BOMType
+	detected  bool
+	detectErr error
+	preserve  bool
+	expect    []BOMType
+	validate  bool
+	validator *utf8Validator
+	sticky    bool
+	skip      int
+	header    [maxBOMLen]byte
+}
+
+// ReaderOption configures a Reader
+type ReaderOption func(*Reader)
+
+// WithPreserveBOM makes the Reader only detect the BOM, and return it to the
+// caller with the rest of the content, so the content flows unchanged.
+func WithPreserveBOM() ReaderOption {
+	return func(r *Reader) {
+		r.preserve = true
+	}
+}
+
+// WithExpect makes the Reader accept only content that starts with one of the
+// given BOM types, where Unknown stands for content without a BOM. For other
+// content, Read returns an *UnexpectedBOMError instead of the content, as do
+// all the following calls, and DetectNow returns it as well.
+func WithExpect(types ...BOMType) ReaderOption {
+	return func(r *Reader) {
+		r.expect = append([]BOMType(nil), types...)
+	}
+}
+
+// WithValidateUTF8 makes the Reader validate content that starts with a UTF-8
+// BOM or without a BOM, as it is read. When an invalid UTF-8 sequence is found,
+// the bytes before it are returned with an *InvalidUTF8Error, and so do all the
+// following calls. Content with any other BOM is not validated.
+//
+// The validation does not hold back bytes, so when the wrapped reader cuts a
+// sequence, its first bytes might be returned before the rest of it shows that
+// it is invalid. The offset of the error is exact either way.
+func WithValidateUTF8() ReaderOption {
+	return func(r *Reader) {
+		r.validate = true
+	}
+}
+
+// NewReader creates a new Reader on top of r, and configures it with opts.
+//
+// The BOM detection happens on the first call to Read (or to DetectNow), and
+// only the bytes that are not part of the BOM are returned to the caller. The
+// detection reads from r as many times as needed, and stops as soon as the
+// bytes that were read cannot be the beginning of a longer BOM, so r may
+// return its content in any number of reads.
+//
+// When r ends before the bytes decide, the detection is made on the bytes that
+// were read: FF FE is UTF16LE even though it might have been the beginning of
+// UTF32LE, and the bytes of a partial BOM, such as EF BB, are not a BOM and are
+// returned as content. A reader that keeps returning no bytes and no error
+// fails the detection with io.ErrNoProgress, instead of blocking forever.
+func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
+	reader := &Reader{reader: r}
+	for _, opt := range opts {
+		opt(reader)
+	}
+
+	return reader
+}
+
+// NewReadCloser creates a new Reader on top of rc, and configures it with opts,
+// for a source that must be closed, such as *os.File or the body of an
+// http.Response. Closing the returned reader closes rc.
+func NewReadCloser(rc io.ReadCloser, opts ...ReaderOption) io.ReadCloser {
+	return NewReader(rc, opts...)
+}
+
+// Close is an implementation of io.Closer interface. It closes the wrapped
+// reader when it implements io.Closer, and does nothing otherwise.
+func (r *Reader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// fill reads from the wrapped reader until at least n bytes are buffered, or
+// the wrapped reader returns an error, that is kept for the next calls
+func (r *Reader) fill(n int) {
+	var chunk [utf8.UTFMax]byte
+	for empty := 0; len(r.buffer) < n && r.err == nil; {
+		read, err := r.reader.Read(chunk[:n-len(r.buffer)])
+		r.buffer = append(r.buffer, chunk[:read]...)
+		r.err = err
+
+		if read > 0 {
+			empty = 0
+		} else if empty++; empty >= maxEmptyReads && err == nil {
+			r.err = io.ErrNoProgress
+		}
+	}
+}
+
+// takeErr returns the kept error of the wrapped reader, which is reported only
+// once, as Read does, unless it is an error of r itself
+func (r *Reader) takeErr() error {
+	err := r.err
+	if !r.sticky {
+		r.err = nil
+	}
+	return err
+}
+
+// ReadByte is an implementation of io.ByteReader interface, and returns the
+// next byte of the content without the BOM.
+func (r *Reader) ReadByte() (byte, error) {
+	if !r.detected {
+		r.detect()
+	}
+
+	r.fill(1)
+	if len(r.buffer) == 0 {
+		_, err := r.checkUTF8(nil, r.takeErr())
+		return 0, err
+	}
+	if _, err := r.checkUTF8(r.buffer[:1], nil); err != nil {
+		return 0, err
+	}
+
+	b := r.buffer[0]
+	r.buffer = r.buffer[1:]
+	return b, nil
+}
+
+// ReadRune is an implementation of io.RuneReader interface, and returns the
+// next char of the content without the BOM, decoded by the detected BOM type:
+// UTF-16 and UTF-32 content is decoded by its code units, and any other
+// content, including content without a BOM, is decoded as UTF-8.
+//
+// The size is the number of bytes the char takes in the content. Invalid
+// content, such as an unpaired surrogate, returns utf8.RuneError with the size
+// of the invalid code unit, as utf8.DecodeRune does.
+func (r *Reader) ReadRune() (ch rune, size int, err error) {
+	if !r.detected {
+		r.detect()
+	}
+
+	_, unit := encodedBOMRune(r.bomType)
+	order, _ := r.bomType.ByteOrder()
+
+	r.fill(unit)
+	switch {
+	case len(r.buffer) == 0:
+		_, err = r.checkUTF8(nil, r.takeErr())
+		return 0, 0, err
+	case len(r.buffer) < unit:
+		// the content ended in the middle of a code unit
+		size = len(r.buffer)
+		r.buffer = r.buffer[size:]
+		return utf8.RuneError, size, nil
+	}
+
+	switch unit {
+	case 2:
+		ch, size = rune(order.Uint16(r.buffer)), 2
+		if utf16.IsSurrogate(ch) {
+			r.fill(4)
+			ch = utf8.RuneError
+			if len(r.buffer) >= 4 {
+				if pair := utf16.DecodeRune(rune(order.Uint16(r.buffer)), rune(order.Uint16(r.buffer[2:]))); pair != utf8.RuneError {
+					ch, size = pair, 4
+				}
+			}
+		}
+	case 4:
+		ch, size = rune(order.Uint32(r.buffer)), 4
+		if !utf8.ValidRune(ch) {
+			ch = utf8.RuneError
+		}
+	default:
+		// read only the bytes the rune needs, as bufio.Reader does, so a
+		// stream that waits after a complete rune does not block
+		for len(r.buffer) < utf8.UTFMax && !utf8.FullRune(r.buffer) && r.err == nil {
+			r.fill(len(r.buffer) + 1)
+		}
+		ch, size = utf8.DecodeRune(r.buffer)
+		if _, err := r.checkUTF8(r.buffer[:size], nil); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	r.buffer = r.buffer[size:]
+	return ch, size, nil
+}
+
+// WriteTo is an implementation of io.WriterTo interface, so io.Copy moves the
+// content without the BOM to w without an intermediate buffer. After the bytes
+// that were read while detecting, the wrapped reader is copied to w by its own
+// WriteTo, or by the ReadFrom of w, when they are available.
+func (r *Reader) WriteTo(w io.Writer) (int64, error) {
+	if !r.detected {
+		r.detect()
+	}
+	if r.validator != nil {
+		// the content must pass through Read in order to be validated
+		return io.Copy(w, struct{ io.Reader }{r})
+	}
+
+	var written int64
+	if len(r.buffer) > 0 {
+		n, err := w.Write(r.buffer)
+		r.buffer = r.buffer[n:]
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if r.err != nil {
+		err := r.takeErr()
+		if err == io.EOF {
+			err = nil
+		}
+		return written, err
+	}
+
+	n, err := io.Copy(w, r.reader)
+	return written + n, err
+}
+
+// Seek is an implementation of io.Seeker interface, for a Reader that wraps a
+// reader that implements io.Seeker. The offsets are of the content without the
+// BOM, so position 0 is the first byte after the BOM. With WithPreserveBOM,
+// the offsets are of the wrapped reader as is.
+//
+// Seek detects the BOM first if it was not detected yet. ErrNotSeeker is
+// returned when the wrapped reader does not implement io.Seeker. When
+// SeekEnd leads into the BOM, ErrNegativePosition is returned, and r is left
+// at the beginning of the content.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := r.reader.(io.Seeker)
+	if !ok {
+		return 0, ErrNotSeeker
+	}
+
+	if !r.detected {
+		r.detect()
+	}
+	if r.sticky {
+		return 0, r.err
+	}
+
+	skip := int64(r.skip)
+	switch whence {
+	case io.SeekStart:
+		offset += skip
+	case io.SeekCurrent:
+		current, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		// the buffered bytes were read from the wrapped reader, but not
+		// from r
+		offset += current - int64(len(r.buffer))
+		whence = io.SeekStart
+	case io.SeekEnd:
+	default:
+		return 0, errors.New("gobom: invalid whence")
+	}
+
+	if whence != io.SeekEnd && offset < skip {
+		return 0, ErrNegativePosition
+	}
+
+	position, err := seeker.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	// the wrapped reader moved, so the buffered bytes are not of its position
+	r.buffer = nil
+	r.err = nil
+	if position < skip {
+		// a position inside the BOM is not a position of the content, so
+		// the beginning of the content is used instead
+		err = ErrNegativePosition
+		if _, seekErr := seeker.Seek(skip, io.SeekStart); seekErr != nil {
+			err = seekErr
+		}
+		position = skip
+	}
+	if r.validator != nil {
+		r.validator = &utf8Validator{offset: position - skip}
+	}
+
+	return position - skip, err
+}
+
+// Reset discards the state of r, and makes it read from reader, so a Reader can
+// be reused, such as by a sync.Pool, instead of allocating a new one. The
+// options r was created with are kept.
+func (r *Reader) Reset(reader io.Reader) {
+	*r = Reader{
+		reader:   reader,
+		preserve: r.preserve,
+		expect:   r.expect,
+		validate: r.validate,
+	}
+}
+
+// NewReaderWithType creates a new Reader on top of r, configures it with opts,
+// and detects the BOM right away. It returns the reader with the BOM type it
+// found, or the error that r returned while detecting. Reaching the end of r is
+// not an error.
+func NewReaderWithType(r io.Reader, opts ...ReaderOption) (io.Reader, BOMType, error) {
+	reader := NewReader(r, opts...)
+	bomType, err := reader.DetectNow()
+	if err != nil {
+		return nil, Unknown, err
+	}
+
+	return reader, bomType, nil
+}
+
+// detect reads the first bytes of the wrapped reader, and keep anything that
+// is not part of the BOM at the buffer for the next calls of Read.
+func (r *Reader) detect() {
+	r.detected = true
+
+	buffer, err := readBOM(r.reader, r.header[:0])
+	r.err = err
+	if err != io.EOF {
+		r.detectErr = err
+	}
+
+	r.bomType = DetectBOMTypeFromBuffer(buffer)
+	skip := BytesToSkip(buffer)
+	if skip < 0 || r.preserve {
+		skip = 0
+	}
+	r.skip = skip
+	r.buffer = buffer[skip:]
+
+	if r.detectErr == nil && r.expect != nil && !r.expected(r.bomType) {
+		r.buffer = nil
+		r.err = &UnexpectedBOMError{Type: r.bomType, Expected: r.expect}
+		r.detectErr = r.err
+		r.sticky = true
+	}
+
+	if r.validate && (r.bomType == UTF8 || r.bomType == Unknown) {
+		r.validator = &utf8Validator{}
+	}
+}
+
+// checkUTF8 validates p, that is about to be returned with err, when r
+// validates its content. It returns the number of bytes of p that can be
+// returned, and the error to return with them.
+func (r *Reader) checkUTF8(p []byte, err error) (int, error) {
+	if r.validator == nil {
+		return len(p), err
+	}
+
+	n, invalid := r.validator.check(p)
+	if invalid == nil && err == io.EOF {
+		invalid = r.validator.finish()
+	}
+	if invalid != nil {
+		r.buffer = nil
+		r.err = invalid
+		r.sticky = true
+		return n, invalid
+	}
+
+	return n, err
+}
+
+// expected checks if t is one of the BOM types r expects
+func (r *Reader) expected(t BOMType) bool {
+	for _, bomType := range r.expect {
+		if bomType == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readHeader reads up to size bytes from r, even if r returns them one at a
+// time. If r ends before size bytes were read, the error is io.EOF.
+func readHeader(r io.Reader, size int) ([]byte, error) {
+	buffer := make([]byte, size)
+	n, err := io.ReadFull(r, buffer)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return buffer[:n], err
+}
+
+// readBOM reads from r into buffer until the bytes that were read decide the
+// BOM, or r returns an error. The capacity of buffer must be at least
+// maxBOMLen. Unlike readHeader, it does not wait for the size of the
+// longest BOM when the bytes cannot be the beginning of a longer BOM, so a
+// source such as net.Conn or io.Pipe that returns a few bytes and then waits
+// does not block the detection.
+func readBOM(r io.Reader, buffer []byte) ([]byte, error) {
+	for empty := 0; !bomDecided(buffer); {
+		n, err := r.Read(buffer[len(buffer):maxBOMLen])
+		buffer = buffer[:len(buffer)+n]
+		if err != nil {
+			return buffer, err
+		}
+
+		if n > 0 {
+			empty = 0
+		} else if empty++; empty >= maxEmptyReads {
+			return buffer, io.ErrNoProgress
+		}
+	}
+
+	return buffer, nil
+}
+
+// bomDecided checks if no more bytes are needed in order to detect the BOM
+// buffer starts with, because no signature that is longer than buffer starts
+// with it
+func bomDecided(buffer []byte) bool {
+	if len(buffer) >= maxBOMLen {
+		return true
+	}
+
+	for _, sig := range signatures {
+		if len(sig.bytes) > len(buffer) && hasPrefix(sig.bytes, buffer) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DetectBOMTypeFromReader reads at most the size of the longest BOM (5 bytes)
+// from r and detects the BOM type out of them. It stops reading as soon as the
+// bytes that were read decide the BOM, so a source such as net.Conn or
+// io.Pipe that sent a complete BOM and waits does not block the detection.
+//
+// Reaching the end of r is not an error, and the detection is made on the
+// bytes that were read. Any other error is returned as is, with Unknown as the
+// BOM type, so an I/O error can be told apart from a missing BOM.
+func DetectBOMTypeFromReader(r io.Reader) (BOMType, error) {
+	buffer, err := readBOM(r, make([]byte, 0, maxBOMLen))
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+
+	return DetectBOMTypeFromBuffer(buffer), nil
+}
+
+// BOMType returns the type of BOM that was found at the beginning of the
+// wrapped reader.
+//
+// If Read was not called yet, BOMType performs the detection by itself, and
+// therefore it blocks until the wrapped reader provides enough bytes (or
+// returns an error). The bytes that were read are not lost, and will be
+// returned by the next calls to Read.
+func (r *Reader) BOMType() BOMType {
+	bomType, _ := r.DetectNow()
+	return bomType
+}
+
+// DetectNow forces the detection of the BOM, for callers that need the BOM type
+// before they start to read, and returns the error the wrapped reader returned
+// while detecting, if any. Reaching the end of the wrapped reader is not an
+// error.
+//
+// The detection happens only once: when Read already detected the BOM,
+// DetectNow returns the same result without reading, and when DetectNow
+// detected it, Read does not detect it again. The bytes that DetectNow read are
+// returned by the next calls to Read, and so is the error, so a caller that
+// ignores the error of DetectNow still gets it from Read.
+func (r *Reader) DetectNow() (BOMType, error) {
+	if !r.detected {
+		r.detect()
+	}
+
+	return r.bomType, r.detectErr
+}
+
+// Read is an implementation of io.Reader interface.
+// The bytes are taken from Reader, checking for BOM and removing them if
+// necessary.
+func (r *Reader) Read(buffer []byte) (n int, err error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if !r.detected {
+		r.detect()
+	}
+
+	switch {
+	case len(r.buffer) > 0:
+		n = copy(buffer, r.buffer)
+		r.buffer = r.buffer[n:]
+	case r.err != nil:
+		err = r.takeErr()
+	default:
+		n, err = r.reader.Read(buffer)
+	}
+
+	return r.checkUTF8(buffer[:n], err)
+}
@@ -0,0 +1,637 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"empty", []byte{}, []byte{}},
+		{"no bom", []byte("hello world"), []byte("hello world")},
+		{"short no bom", []byte("hi"), []byte("hi")},
+		{"utf8 bom only", []byte{0xEF, 0xBB, 0xBF}, []byte{}},
+		{"utf8", append([]byte{0xEF, 0xBB, 0xBF}, "hello"...), []byte("hello")},
+		{"utf16be", []byte{0xFE, 0xFF, 0x00, 0x41}, []byte{0x00, 0x41}},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 0x41}, []byte{0x00, 0x00, 0x00, 0x41}},
+		{"utf7", []byte("+/v8-hello"), []byte("hello")},
+		{"utf7 variant", []byte("+/v9AGE-"), []byte("+/v9AGE-")},
+	}
+
+	for _, test := range tests {
+		readers := map[string]io.Reader{
+			"plain":    bytes.NewReader(test.input),
+			"one byte": iotest.OneByteReader(bytes.NewReader(test.input)),
+			"half":     iotest.HalfReader(bytes.NewReader(test.input)),
+		}
+		for kind, source := range readers {
+			got, err := io.ReadAll(NewReader(source))
+			if err != nil {
+				t.Errorf("%s/%s: unexpected error: %s", test.name, kind, err)
+				continue
+			}
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("%s/%s: got %v, want %v", test.name, kind, got, test.want)
+			}
+		}
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	source := iotest.TimeoutReader(bytes.NewReader([]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}))
+	_, err := io.ReadAll(NewReader(source))
+	if err != iotest.ErrTimeout {
+		t.Errorf("got %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReaderBOMType(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0xFE, 0xFF, 0x00, 0x41}))
+	if got := reader.BOMType(); got != UTF16BE {
+		t.Errorf("BOMType() = %d, want %d", got, UTF16BE)
+	}
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []byte{0x00, 0x41}; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	reader = NewReader(bytes.NewReader([]byte("hello")))
+	if got := reader.BOMType(); got != Unknown {
+		t.Errorf("BOMType() = %d, want %d", got, Unknown)
+	}
+}
+
+func TestDetectBOMTypeFromReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  BOMType
+	}{
+		{"empty", nil, Unknown},
+		{"no bom", []byte("hello"), Unknown},
+		{"utf8 only", []byte{0xEF, 0xBB, 0xBF}, UTF8},
+		{"utf16le", []byte{0xFF, 0xFE, 0x41, 0x00}, UTF16LE},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00}, UTF32BE},
+	}
+
+	for _, test := range tests {
+		source := iotest.OneByteReader(bytes.NewReader(test.input))
+		got, err := DetectBOMTypeFromReader(source)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, got, test.want)
+		}
+	}
+
+	source := iotest.ErrReader(iotest.ErrTimeout)
+	if _, err := DetectBOMTypeFromReader(source); err != iotest.ErrTimeout {
+		t.Errorf("got %v, want %v", err, iotest.ErrTimeout)
+	}
+
+	// no more bytes are read than the ones that decide the BOM
+	content := bytes.NewReader([]byte("hello"))
+	if got, err := DetectBOMTypeFromReader(iotest.OneByteReader(content)); got != Unknown || err != nil {
+		t.Errorf("got %s, %v, want %s", got, err, Unknown)
+	}
+	if content.Len() != 4 {
+		t.Errorf("%d bytes were left, want 4", content.Len())
+	}
+
+	// a peer that sent a complete BOM and waits
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte{0xEF, 0xBB, 0xBF})
+	if got, err := DetectBOMTypeFromReader(pr); got != UTF8 || err != nil {
+		t.Errorf("got %s, %v, want %s", got, err, UTF8)
+	}
+}
+
+func TestReaderUTF32LE(t *testing.T) {
+	input := []byte{0xFF, 0xFE, 0x00, 0x00, 'a', 0x00, 0x00, 0x00}
+	reader := NewReader(bytes.NewReader(input))
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := input[4:]; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if reader.BOMType() != UTF32LE {
+		t.Errorf("BOMType() = %d, want %d", reader.BOMType(), UTF32LE)
+	}
+}
+
+func TestReaderPipe(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes [][]byte
+		want   []byte
+	}{
+		{"no bom", [][]byte{[]byte("hi")}, []byte("hi")},
+		{"split utf8", [][]byte{{0xEF}, {0xBB}, {0xBF, 'h', 'i'}}, []byte("hi")},
+		{"split utf32le", [][]byte{{0xFF, 0xFE}, {0x00}, {0x00, 'h'}}, []byte{'h'}},
+		{"utf16le", [][]byte{{0xFF, 0xFE}, {'h', 0x00}}, []byte{'h', 0x00}},
+	}
+
+	for _, test := range tests {
+		pr, pw := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			for _, write := range test.writes {
+				pw.Write(write)
+			}
+			// the writer waits, as a peer that expects an answer
+			<-done
+			pw.Close()
+		}()
+
+		result := make(chan []byte)
+		go func() {
+			buffer := make([]byte, len(test.want))
+			n, _ := io.ReadFull(NewReader(pr), buffer)
+			result <- buffer[:n]
+		}()
+
+		select {
+		case got := <-result:
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s: Read is blocked", test.name)
+		}
+		close(done)
+	}
+}
+
+func TestReaderPreserveBOM(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  BOMType
+	}{
+		{"empty", []byte{}, Unknown},
+		{"no bom", []byte("hello"), Unknown},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00}, UTF16LE},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(iotest.OneByteReader(bytes.NewReader(test.input)), WithPreserveBOM())
+		if got := reader.BOMType(); got != test.want {
+			t.Errorf("%s: BOMType() = %s, want %s", test.name, got, test.want)
+		}
+
+		got, err := io.ReadAll(reader)
+		if err != nil || !bytes.Equal(got, test.input) {
+			t.Errorf("%s: got %v, %v, want %v", test.name, got, err, test.input)
+		}
+	}
+}
+
+func TestReaderDetectNow(t *testing.T) {
+	reader := NewReader(iotest.OneByteReader(bytes.NewReader([]byte("\xEF\xBB\xBFhi"))))
+	for i := 0; i < 2; i++ {
+		if got, err := reader.DetectNow(); got != UTF8 || err != nil {
+			t.Errorf("DetectNow() = %s, %v, want %s", got, err, UTF8)
+		}
+	}
+
+	got, err := io.ReadAll(reader)
+	if err != nil || string(got) != "hi" {
+		t.Errorf("got %q, %v, want %q", got, err, "hi")
+	}
+	if got, err := reader.DetectNow(); got != UTF8 || err != nil {
+		t.Errorf("DetectNow() after Read = %s, %v, want %s", got, err, UTF8)
+	}
+
+	reader = NewReader(iotest.ErrReader(iotest.ErrTimeout))
+	if got, err := reader.DetectNow(); got != Unknown || err != iotest.ErrTimeout {
+		t.Errorf("DetectNow() = %s, %v, want %s, %v", got, err, Unknown, iotest.ErrTimeout)
+	}
+	if _, err := reader.Read(make([]byte, 1)); err != iotest.ErrTimeout {
+		t.Errorf("Read() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestNewReaderWithType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantType BOMType
+		want     []byte
+	}{
+		{"empty", []byte{}, Unknown, []byte{}},
+		{"no bom", []byte("hello"), Unknown, []byte("hello")},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8, []byte("hello")},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'h'}, UTF32BE, []byte{0x00, 0x00, 0x00, 'h'}},
+	}
+
+	for _, test := range tests {
+		reader, bomType, err := NewReaderWithType(iotest.HalfReader(bytes.NewReader(test.input)))
+		if err != nil || bomType != test.wantType {
+			t.Errorf("%s: NewReaderWithType() = %s, %v, want %s", test.name, bomType, err, test.wantType)
+			continue
+		}
+
+		got, err := io.ReadAll(reader)
+		if err != nil || !bytes.Equal(got, test.want) {
+			t.Errorf("%s: got %v, %v, want %v", test.name, got, err, test.want)
+		}
+	}
+
+	if _, _, err := NewReaderWithType(iotest.ErrReader(iotest.ErrTimeout)); err != iotest.ErrTimeout {
+		t.Errorf("NewReaderWithType() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReaderReset(t *testing.T) {
+	reader := NewReader(iotest.ErrReader(iotest.ErrTimeout))
+	if _, err := io.ReadAll(reader); err != iotest.ErrTimeout {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inputs := []struct {
+		input    []byte
+		wantType BOMType
+		want     []byte
+	}{
+		{[]byte("\xEF\xBB\xBFhello"), UTF8, []byte("hello")},
+		{[]byte("world"), Unknown, []byte("world")},
+		{[]byte{0xFF, 0xFE, 'h', 0x00}, UTF16LE, []byte{'h', 0x00}},
+	}
+
+	for _, input := range inputs {
+		reader.Reset(bytes.NewReader(input.input))
+		got, err := io.ReadAll(reader)
+		if err != nil || !bytes.Equal(got, input.want) {
+			t.Errorf("Reset(%v): got %v, %v, want %v", input.input, got, err, input.want)
+		}
+		if bomType := reader.BOMType(); bomType != input.wantType {
+			t.Errorf("Reset(%v): BOMType() = %s, want %s", input.input, bomType, input.wantType)
+		}
+	}
+
+	reader = NewReader(nil, WithPreserveBOM())
+	reader.Reset(bytes.NewReader([]byte("\xEF\xBB\xBFhi")))
+	if got, _ := io.ReadAll(reader); string(got) != "\xEF\xBB\xBFhi" {
+		t.Errorf("Reset() did not keep WithPreserveBOM, got %q", got)
+	}
+}
+
+// closeRecorder records whether it was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+	err    error
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func TestReaderClose(t *testing.T) {
+	errClose := errors.New("close error")
+	source := &closeRecorder{Reader: bytes.NewReader([]byte("\xEF\xBB\xBFhi")), err: errClose}
+
+	reader := NewReadCloser(source)
+	got, err := io.ReadAll(reader)
+	if err != nil || string(got) != "hi" {
+		t.Errorf("got %q, %v, want %q", got, err, "hi")
+	}
+	if err := reader.Close(); err != errClose || !source.closed {
+		t.Errorf("Close() = %v, closed %t, want %v, closed", err, source.closed, errClose)
+	}
+
+	if err := NewReader(bytes.NewReader(nil)).Close(); err != nil {
+		t.Errorf("Close() of a reader that is not a closer = %v, want nil", err)
+	}
+}
+
+func TestReaderSeek(t *testing.T) {
+	input := []byte("\xEF\xBB\xBFhello world")
+
+	tests := []struct {
+		name         string
+		offset       int64
+		whence       int
+		wantPosition int64
+		want         string
+		wantErr      error
+	}{
+		{"start", 0, io.SeekStart, 0, "hello world", nil},
+		{"start offset", 6, io.SeekStart, 6, "world", nil},
+		{"current", 2, io.SeekCurrent, 2, "llo world", nil},
+		{"end", -5, io.SeekEnd, 6, "world", nil},
+		{"before start", -1, io.SeekStart, 0, "hello world", ErrNegativePosition},
+		{"inside bom", -12, io.SeekEnd, 0, "hello world", ErrNegativePosition},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(bytes.NewReader(input))
+		position, err := reader.Seek(test.offset, test.whence)
+		if err != test.wantErr {
+			t.Errorf("%s: Seek() error = %v, want %v", test.name, err, test.wantErr)
+			continue
+		}
+
+		got, _ := io.ReadAll(reader)
+		if position != test.wantPosition || string(got) != test.want {
+			t.Errorf("%s: Seek() = %d, read %q, want %d, %q", test.name, position, got, test.wantPosition, test.want)
+		}
+	}
+
+	reader := NewReader(bytes.NewReader(input))
+	buffer := make([]byte, 3)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if position, err := reader.Seek(0, io.SeekCurrent); position != 3 || err != nil {
+		t.Errorf("Seek(0, current) after read = %d, %v, want 3", position, err)
+	}
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if position, err := reader.Seek(0, io.SeekStart); position != 0 || err != nil {
+		t.Errorf("Seek(0, start) after EOF = %d, %v, want 0", position, err)
+	}
+	if got, _ := io.ReadAll(reader); string(got) != "hello world" {
+		t.Errorf("read after rewind got %q, want %q", got, "hello world")
+	}
+
+	preserve := NewReader(bytes.NewReader(input), WithPreserveBOM())
+	if position, err := preserve.Seek(3, io.SeekStart); position != 3 || err != nil {
+		t.Errorf("Seek() with WithPreserveBOM = %d, %v, want 3", position, err)
+	}
+
+	if _, err := NewReader(iotest.HalfReader(bytes.NewReader(input))).Seek(0, io.SeekStart); err != ErrNotSeeker {
+		t.Errorf("Seek() error = %v, want %v", err, ErrNotSeeker)
+	}
+}
+
+// writerToRecorder records whether its WriteTo was used
+type writerToRecorder struct {
+	*bytes.Reader
+	used bool
+}
+
+func (w *writerToRecorder) WriteTo(dst io.Writer) (int64, error) {
+	w.used = true
+	return w.Reader.WriteTo(dst)
+}
+
+func TestReaderWriteTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"empty", []byte{}, []byte{}},
+		{"short", []byte("hi"), []byte("hi")},
+		{"utf8", []byte("\xEF\xBB\xBFhello world"), []byte("hello world")},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}, []byte{'h', 0x00, 'i', 0x00}},
+	}
+
+	for _, test := range tests {
+		source := &writerToRecorder{Reader: bytes.NewReader(test.input)}
+		var buffer bytes.Buffer
+		n, err := io.Copy(&buffer, NewReader(source))
+		if err != nil || n != int64(len(test.want)) || !bytes.Equal(buffer.Bytes(), test.want) {
+			t.Errorf("%s: io.Copy() = %d, %v, %v, want %v", test.name, n, err, buffer.Bytes(), test.want)
+		}
+		if len(test.input) > maxBOMLen && !source.used {
+			t.Errorf("%s: WriteTo of the wrapped reader was not used", test.name)
+		}
+	}
+
+	source := iotest.TimeoutReader(bytes.NewReader([]byte("\xEF\xBB\xBFhello world")))
+	if _, err := NewReader(source).WriteTo(io.Discard); err != iotest.ErrTimeout {
+		t.Errorf("WriteTo() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReaderReadByte(t *testing.T) {
+	reader := NewReader(iotest.OneByteReader(bytes.NewReader([]byte("\xEF\xBB\xBFhi"))))
+
+	var got []byte
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, b)
+	}
+
+	if string(got) != "hi" {
+		t.Errorf("got %q, want %q", got, "hi")
+	}
+}
+
+func TestReaderReadRune(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		want      []rune
+		wantSizes []int
+	}{
+		{"empty", []byte{}, nil, nil},
+		{"no bom", []byte("aé"), []rune{'a', 'é'}, []int{1, 2}},
+		{"utf8", []byte("\xEF\xBB\xBFa€"), []rune{'a', '€'}, []int{1, 3}},
+		{"utf8 invalid", []byte("a\xFFb"), []rune{'a', utf8.RuneError, 'b'}, []int{1, 1, 1}},
+		{"utf16le", []byte{0xFF, 0xFE, 'a', 0x00, 0x3D, 0xD8, 0x00, 0xDE}, []rune{'a', 0x1F600}, []int{2, 4}},
+		{"utf16be", []byte{0xFE, 0xFF, 0x00, 'a', 0x20, 0xAC}, []rune{'a', '€'}, []int{2, 2}},
+		{"utf16le unpaired", []byte{0xFF, 0xFE, 0x3D, 0xD8, 'a', 0x00}, []rune{utf8.RuneError, 'a'}, []int{2, 2}},
+		{"utf16le odd", []byte{0xFF, 0xFE, 'a', 0x00, 'b'}, []rune{'a', utf8.RuneError}, []int{2, 1}},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x01, 0xF6, 0x00}, []rune{0x1F600}, []int{4}},
+		{"utf32le invalid", []byte{0xFF, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x11, 0x00}, []rune{utf8.RuneError}, []int{4}},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(iotest.OneByteReader(bytes.NewReader(test.input)))
+
+		var got []rune
+		var sizes []int
+		for {
+			ch, size, err := reader.ReadRune()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			got = append(got, ch)
+			sizes = append(sizes, size)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(test.want) || fmt.Sprint(sizes) != fmt.Sprint(test.wantSizes) {
+			t.Errorf("%s: got %U %v, want %U %v", test.name, got, sizes, test.want, test.wantSizes)
+		}
+	}
+
+	reader := NewReader(bytes.NewReader([]byte{0xFF, 0xFE, 'a', 0x00}), WithPreserveBOM())
+	if ch, size, err := reader.ReadRune(); ch != BOMRune || size != 2 || err != nil {
+		t.Errorf("ReadRune() with WithPreserveBOM = %U, %d, %v, want %U, 2", ch, size, err, BOMRune)
+	}
+}
+
+func TestReaderReadRunePipe(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte("\xEF\xBB\xBF\xC3\xA9"))
+
+	type result struct {
+		ch   rune
+		size int
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ch, size, err := NewReader(pr).ReadRune()
+		done <- result{ch, size, err}
+	}()
+
+	select {
+	case got := <-done:
+		if got.ch != 'é' || got.size != 2 || got.err != nil {
+			t.Errorf("ReadRune() = %q, %d, %v, want %q, 2", got.ch, got.size, got.err, 'é')
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ReadRune() blocked after a complete rune")
+	}
+}
+
+func TestReaderExpect(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		expect  []BOMType
+		want    []byte
+		wantErr bool
+	}{
+		{"utf8 bom", []byte("\xEF\xBB\xBFhi"), []BOMType{UTF8, Unknown}, []byte("hi"), false},
+		{"no bom", []byte("hi"), []BOMType{UTF8, Unknown}, []byte("hi"), false},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00}, []BOMType{UTF8, Unknown}, nil, true},
+		{"bom required", []byte("hi"), []BOMType{UTF8}, nil, true},
+		{"empty", []byte{}, []BOMType{UTF8}, nil, true},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(bytes.NewReader(test.input), WithExpect(test.expect...))
+		got, err := io.ReadAll(reader)
+
+		var unexpected *UnexpectedBOMError
+		if errors.As(err, &unexpected) != test.wantErr {
+			t.Errorf("%s: error = %v, want error %t", test.name, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !bytes.Equal(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+		if test.wantErr {
+			if len(got) != 0 {
+				t.Errorf("%s: got %v, want no content", test.name, got)
+			}
+			if _, err := reader.Read(make([]byte, 1)); err != unexpected {
+				t.Errorf("%s: second Read() error = %v, want %v", test.name, err, unexpected)
+			}
+			if _, err := reader.DetectNow(); err != unexpected {
+				t.Errorf("%s: DetectNow() error = %v, want %v", test.name, err, unexpected)
+			}
+		}
+	}
+
+	_, err := io.ReadAll(NewReader(bytes.NewReader([]byte{0xFF, 0xFE, 'h', 0x00}), WithExpect(UTF8, Unknown)))
+	want := "gobom: unexpected BOM type UTF-16LE, expected one of [UTF-8 Unknown]"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
+
+// trickleReader returns its content one or two bytes at a time, with reads
+// that return nothing in between
+type trickleReader struct {
+	content []byte
+	reads   int
+}
+
+func (t *trickleReader) Read(buffer []byte) (int, error) {
+	t.reads++
+	if t.reads%3 == 0 {
+		return 0, nil
+	}
+	if len(t.content) == 0 {
+		return 0, io.EOF
+	}
+
+	size := 1 + t.reads%2
+	if size > len(buffer) {
+		size = len(buffer)
+	}
+	n := copy(buffer[:size], t.content)
+	t.content = t.content[n:]
+	return n, nil
+}
+
+// emptyReader never returns bytes or an error
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) {
+	return 0, nil
+}
+
+func TestReaderTrickle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantType BOMType
+		want     []byte
+	}{
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8, []byte("hello")},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, UTF32LE, []byte{'h', 0x00, 0x00, 0x00}},
+		{"utf16le at eof", []byte{0xFF, 0xFE}, UTF16LE, []byte{}},
+		{"partial bom at eof", []byte{0xEF, 0xBB}, Unknown, []byte{0xEF, 0xBB}},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(&trickleReader{content: test.input})
+		if bomType, err := reader.DetectNow(); bomType != test.wantType || err != nil {
+			t.Errorf("%s: DetectNow() = %s, %v, want %s", test.name, bomType, err, test.wantType)
+		}
+
+		got, err := io.ReadAll(reader)
+		if err != nil || !bytes.Equal(got, test.want) {
+			t.Errorf("%s: got %v, %v, want %v", test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := NewReader(emptyReader{}).DetectNow(); err != io.ErrNoProgress {
+		t.Errorf("DetectNow() error = %v, want %v", err, io.ErrNoProgress)
+	}
+
+	reader := NewReader(io.MultiReader(bytes.NewReader([]byte{0xFF, 0xFE, 'a', 0x00}), emptyReader{}))
+	reader.ReadRune()
+	if _, _, err := reader.ReadRune(); err != io.ErrNoProgress {
+		t.Errorf("ReadRune() error = %v, want %v", err, io.ErrNoProgress)
+	}
+}
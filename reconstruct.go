@@ -0,0 +1,79 @@
+package gobom
+
+import "io"
+
+// prefixReader returns the bytes of prefix, then an error that was kept, and
+// then the content of reader
+type prefixReader struct {
+	prefix []byte
+	err    error
+	reader io.Reader
+}
+
+// Reconstruct glues prefix, the first bytes that were already read from a
+// stream, back in front of r, the rest of that stream, without the BOM prefix
+// starts with, and returns the BOM type it found.
+//
+// When prefix is too short to decide the BOM, such as FF FE that might be the
+// beginning of UTF32LE BOM, the bytes that are needed are read from r. An error
+// that r returns while doing so is returned by the returned reader, after the
+// bytes of prefix.
+//
+// prefix is not copied, and must not be modified while the returned reader is
+// in use. When nothing but the BOM was read, r is returned as is.
+func Reconstruct(prefix []byte, r io.Reader) (io.Reader, BOMType) {
+	var err error
+	if !bomDecided(prefix) {
+		header := make([]byte, len(prefix), maxBOMLen)
+		copy(header, prefix)
+		prefix, err = readBOM(r, header)
+	}
+
+	bomType, size := matchSignatureLen(signatures, prefix)
+	prefix = prefix[size:]
+	if len(prefix) == 0 && err == nil {
+		return r, bomType
+	}
+
+	return &prefixReader{prefix: prefix, err: err, reader: r}, bomType
+}
+
+// Read is an implementation of io.Reader interface
+func (p *prefixReader) Read(buffer []byte) (int, error) {
+	if len(p.prefix) > 0 {
+		n := copy(buffer, p.prefix)
+		p.prefix = p.prefix[n:]
+		return n, nil
+	}
+
+	if p.err != nil {
+		err := p.err
+		p.err = nil
+		return 0, err
+	}
+
+	return p.reader.Read(buffer)
+}
+
+// WriteTo is an implementation of io.WriterTo interface, that writes the
+// prefix at once, and lets io.Copy pick the best way to copy the rest
+func (p *prefixReader) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(p.prefix)
+	written := int64(n)
+	p.prefix = p.prefix[n:]
+	if err != nil {
+		return written, err
+	}
+
+	if p.err != nil {
+		err := p.err
+		p.err = nil
+		if err == io.EOF {
+			return written, nil
+		}
+		return written, err
+	}
+
+	copied, err := io.Copy(w, p.reader)
+	return written + copied, err
+}
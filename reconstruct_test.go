@@ -0,0 +1,64 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReconstruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix []byte
+		rest   string
+		want   string
+		bom    BOMType
+	}{
+		{"utf8", []byte("\xEF\xBB\xBFab"), "cd", "abcd", UTF8},
+		{"bom only", []byte("\xEF\xBB\xBF"), "cd", "cd", UTF8},
+		{"no bom", []byte("ab"), "cd", "abcd", Unknown},
+		{"empty prefix", nil, "\xEF\xBB\xBFcd", "cd", UTF8},
+		{"partial bom", []byte{0xEF}, "\xBB\xBFcd", "cd", UTF8},
+		{"longer bom", []byte{0xFF, 0xFE}, "\x00\x00a\x00\x00\x00", "a\x00\x00\x00", UTF32LE},
+		{"short utf16le", []byte{0xFF, 0xFE}, "a\x00", "a\x00", UTF16LE},
+		{"utf7 variant", []byte("+/v9"), "AGE-", "+/v9AGE-", UTF7},
+		{"nothing", nil, "", "", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, bomType := Reconstruct(tt.prefix, iotest.OneByteReader(strings.NewReader(tt.rest)))
+			if bomType != tt.bom {
+				t.Errorf("Reconstruct() BOM = %s, want %s", bomType, tt.bom)
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+
+			r, _ = Reconstruct(tt.prefix, strings.NewReader(tt.rest))
+			var out bytes.Buffer
+			if _, err := io.Copy(&out, r); err != nil || out.String() != tt.want {
+				t.Errorf("io.Copy() got %q, %v, want %q", out.String(), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconstructError(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	r, bomType := Reconstruct([]byte{0xFF}, iotest.ErrReader(errBroken))
+	if bomType != Unknown {
+		t.Errorf("Reconstruct() BOM = %s, want %s", bomType, Unknown)
+	}
+
+	got, err := io.ReadAll(r)
+	if string(got) != "\xFF" || err != errBroken {
+		t.Errorf("got %q, %v, want %q, %v", got, err, "\xFF", errBroken)
+	}
+}
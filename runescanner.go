@@ -0,0 +1,80 @@
+package gobom
+
+import (
+	"bufio"
+	"io"
+)
+
+// RuneScanner is an implementation of io.RuneScanner that skips the BOM char
+// (U+FEFF) at the beginning of the wrapped rune reader, and optionally the BOM
+// chars after it, so lexers that are built on ReadRune and UnreadRune do not
+// have to handle them.
+type RuneScanner struct {
+	reader   io.RuneReader
+	interior bool
+	started  bool
+	last     rune
+	lastSize int
+	unread   bool
+}
+
+// RuneScannerOption configures a RuneScanner
+type RuneScannerOption func(*RuneScanner)
+
+// WithSkipInteriorBOMs makes the RuneScanner skip the BOM chars after the
+// beginning of the content as well, such as those that are left when BOM
+// prefixed files are concatenated.
+func WithSkipInteriorBOMs() RuneScannerOption {
+	return func(s *RuneScanner) {
+		s.interior = true
+	}
+}
+
+// NewRuneScanner creates a new RuneScanner on top of r, and configures it with
+// opts. The runes are decoded by r, so for UTF-16 or UTF-32 content, r can be
+// a Reader, which decodes by the detected BOM type.
+func NewRuneScanner(r io.RuneReader, opts ...RuneScannerOption) *RuneScanner {
+	s := &RuneScanner{reader: r, lastSize: -1}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// ReadRune is an implementation of io.RuneReader interface
+func (s *RuneScanner) ReadRune() (ch rune, size int, err error) {
+	if s.unread {
+		s.unread = false
+		return s.last, s.lastSize, nil
+	}
+
+	for {
+		ch, size, err = s.reader.ReadRune()
+		if err != nil {
+			s.lastSize = -1
+			return ch, size, err
+		}
+
+		if ch == BOMRune && (!s.started || s.interior) {
+			s.started = true
+			continue
+		}
+
+		s.started = true
+		s.last, s.lastSize = ch, size
+		return ch, size, nil
+	}
+}
+
+// UnreadRune is an implementation of io.RuneScanner interface. As with
+// bufio.Reader, only the last rune that was read can be unread, and
+// bufio.ErrInvalidUnreadRune is returned otherwise.
+func (s *RuneScanner) UnreadRune() error {
+	if s.unread || s.lastSize < 0 {
+		return bufio.ErrInvalidUnreadRune
+	}
+
+	s.unread = true
+	return nil
+}
@@ -0,0 +1,73 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRuneScanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		reader   io.RuneReader
+		interior bool
+		want     string
+	}{
+		{"empty", strings.NewReader(""), false, ""},
+		{"no bom", strings.NewReader("abc"), false, "abc"},
+		{"leading", strings.NewReader("\uFEFFabc"), false, "abc"},
+		{"interior kept", strings.NewReader("\uFEFFa\uFEFFb"), false, "a\uFEFFb"},
+		{"interior skipped", strings.NewReader("\uFEFFa\uFEFFb\uFEFF"), true, "ab"},
+		{"utf16le reader", NewReader(bytes.NewReader([]byte{0xFF, 0xFE, 0xFF, 0xFE, 'a', 0x00})), false, "a"},
+	}
+
+	for _, test := range tests {
+		var opts []RuneScannerOption
+		if test.interior {
+			opts = append(opts, WithSkipInteriorBOMs())
+		}
+		scanner := NewRuneScanner(test.reader, opts...)
+
+		var got []rune
+		for {
+			ch, _, err := scanner.ReadRune()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			got = append(got, ch)
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, string(got), test.want)
+		}
+	}
+}
+
+func TestRuneScannerUnreadRune(t *testing.T) {
+	scanner := NewRuneScanner(strings.NewReader("\uFEFFaé"))
+
+	if err := scanner.UnreadRune(); err != bufio.ErrInvalidUnreadRune {
+		t.Errorf("UnreadRune() before ReadRune = %v, want %v", err, bufio.ErrInvalidUnreadRune)
+	}
+
+	if ch, size, _ := scanner.ReadRune(); ch != 'a' || size != 1 {
+		t.Errorf("ReadRune() = %q, %d, want 'a', 1", ch, size)
+	}
+	if err := scanner.UnreadRune(); err != nil {
+		t.Errorf("UnreadRune() = %v", err)
+	}
+	if err := scanner.UnreadRune(); err != bufio.ErrInvalidUnreadRune {
+		t.Errorf("second UnreadRune() = %v, want %v", err, bufio.ErrInvalidUnreadRune)
+	}
+
+	for _, want := range "aé" {
+		if ch, _, err := scanner.ReadRune(); ch != want || err != nil {
+			t.Errorf("ReadRune() = %q, %v, want %q", ch, err, want)
+		}
+	}
+}
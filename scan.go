@@ -0,0 +1,25 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+)
+
+// ScanLinesNoBOM is a bufio.SplitFunc that behaves like bufio.ScanLines, but
+// removes a UTF-8 BOM from the beginning of the first line, so the first field
+// of line oriented content is not "\ufeffheader".
+//
+// Since a split function does not know where a line is at the content, a UTF-8
+// BOM is removed from the beginning of any line, which also cleans the BOMs
+// that are left when BOM prefixed files are concatenated.
+func ScanLinesNoBOM(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	switch {
+	case bytes.HasPrefix(data, UTF8Bom):
+		return len(UTF8Bom), nil, nil
+	case !atEOF && len(data) > 0 && len(data) < len(UTF8Bom) && bytes.HasPrefix(UTF8Bom, data):
+		// the BOM might be cut, request more data
+		return 0, nil, nil
+	}
+
+	return bufio.ScanLines(data, atEOF)
+}
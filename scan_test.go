@@ -0,0 +1,39 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanLinesNoBOM(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"no bom", "a,b\r\nc,d\n", []string{"a,b", "c,d"}},
+		{"bom", "\xEF\xBB\xBFheader\nrow", []string{"header", "row"}},
+		{"bom only", "\xEF\xBB\xBF", nil},
+		{"bom empty line", "\xEF\xBB\xBF\nrow", []string{"", "row"}},
+		{"concatenated", "\xEF\xBB\xBFa\n\xEF\xBB\xBFb\n", []string{"a", "b"}},
+		{"not a bom", "\xEFx\n", []string{"\xEFx"}},
+	}
+
+	for _, test := range tests {
+		scanner := bufio.NewScanner(iotest.OneByteReader(bytes.NewReader([]byte(test.input))))
+		scanner.Split(ScanLinesNoBOM)
+
+		var got []string
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+
+		if err := scanner.Err(); err != nil || fmt.Sprintf("%q", got) != fmt.Sprintf("%q", test.want) {
+			t.Errorf("%s: got %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package gobom
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// DetectFromBufio detects the BOM type of br by using Peek, so br stays at the
+// beginning of its content, and a parser that reads from br gets the BOM as
+// well. As with Reader, no more bytes than needed are waited for.
+//
+// Reaching the end of br is not an error, and any other error is returned as
+// is, with Unknown as the BOM type.
+func DetectFromBufio(br *bufio.Reader) (BOMType, error) {
+	var head []byte
+	for size := 1; !bomDecided(head); size++ {
+		var err error
+		head, err = br.Peek(size)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Unknown, err
+		}
+	}
+
+	return DetectBOMTypeFromBuffer(head), nil
+}
+
+// DetectBOMTypeFromReaderAt reads at most the size of the longest BOM from the
+// beginning of r, and detects the BOM type out of them. Since it uses ReadAt,
+// no stream position is changed, and it can be used concurrently on the same
+// r, such as a memory mapped file, a zip entry, or a range reader.
+//
+// Reaching the end of r is not an error, and any other error is returned as
+// is, with Unknown as the BOM type.
+func DetectBOMTypeFromReaderAt(r io.ReaderAt) (BOMType, error) {
+	buffer := make([]byte, maxBOMLen)
+	n, err := r.ReadAt(buffer, 0)
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+
+	return DetectBOMTypeFromBuffer(buffer[:n]), nil
+}
+
+// DetectBOMTypeFromReadSeeker detects the BOM type of r from its beginning,
+// and leaves r positioned at the first byte after the BOM, or at the
+// beginning when there is no BOM, so r can be handed to a parser as is.
+//
+// Reaching the end of r is not an error, and any other error is returned as
+// is, with Unknown as the BOM type.
+func DetectBOMTypeFromReadSeeker(r io.ReadSeeker) (BOMType, error) {
+	bomType, _, err := seekPastBOM(r, 0)
+	return bomType, err
+}
+
+// seekPastBOM detects the BOM type of r at offset, and seeks r to the first
+// byte after the BOM, or to offset when there is no BOM. It returns the size of
+// the BOM as well.
+func seekPastBOM(r io.ReadSeeker, offset int64) (BOMType, int64, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return Unknown, 0, err
+	}
+
+	buffer, err := readHeader(r, maxBOMLen)
+	if err != nil && err != io.EOF {
+		return Unknown, 0, err
+	}
+
+	bomType, size := matchSignatureLen(signatures, buffer)
+	if _, err := r.Seek(offset+int64(size), io.SeekStart); err != nil {
+		return Unknown, 0, err
+	}
+
+	return bomType, int64(size), nil
+}
+
+// SkipBOM advances r past the BOM at its current position, if any, and returns
+// the BOM type with the number of bytes that were skipped. When there is no
+// BOM, r is left at its position, and 0 is returned.
+//
+// Reaching the end of r is not an error, and any other error is returned as
+// is, with Unknown as the BOM type.
+func SkipBOM(r io.ReadSeeker) (BOMType, int64, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Unknown, 0, err
+	}
+
+	return seekPastBOM(r, offset)
+}
+
+// DetectFile opens the file at path, reads at most the size of the longest BOM
+// from its beginning, detects the BOM type out of them, and closes it.
+//
+// An empty file is not an error, and any error of opening or reading the file
+// is returned as is, with Unknown as the BOM type.
+func DetectFile(path string) (BOMType, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Unknown, err
+	}
+	defer file.Close()
+
+	return DetectBOMTypeFromReader(file)
+}
@@ -0,0 +1,177 @@
+package gobom
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectFromBufio(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  BOMType
+	}{
+		{"empty", []byte{}, Unknown},
+		{"no bom", []byte("hello"), Unknown},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00}, UTF16LE},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, UTF32LE},
+		{"bom only", []byte{0xFE, 0xFF}, UTF16BE},
+	}
+
+	for _, test := range tests {
+		br := bufio.NewReader(iotest.OneByteReader(bytes.NewReader(test.input)))
+		got, err := DetectFromBufio(br)
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectFromBufio() = %s, %v, want %s", test.name, got, err, test.want)
+		}
+
+		content, err := io.ReadAll(br)
+		if err != nil || !bytes.Equal(content, test.input) {
+			t.Errorf("%s: content after DetectFromBufio() = %v, %v, want %v", test.name, content, err, test.input)
+		}
+	}
+
+	br := bufio.NewReader(iotest.ErrReader(iotest.ErrTimeout))
+	if got, err := DetectFromBufio(br); got != Unknown || err != iotest.ErrTimeout {
+		t.Errorf("DetectFromBufio() = %s, %v, want %s, %v", got, err, Unknown, iotest.ErrTimeout)
+	}
+}
+
+// errReaderAt fails every ReadAt
+type errReaderAt struct{}
+
+func (errReaderAt) ReadAt([]byte, int64) (int, error) {
+	return 0, iotest.ErrTimeout
+}
+
+func TestDetectBOMTypeFromReaderAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  BOMType
+	}{
+		{"empty", []byte{}, Unknown},
+		{"no bom", []byte("hello"), Unknown},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8},
+		{"short utf16be", []byte{0xFE, 0xFF}, UTF16BE},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00}, UTF32BE},
+	}
+
+	for _, test := range tests {
+		r := bytes.NewReader(test.input)
+		got, err := DetectBOMTypeFromReaderAt(r)
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectBOMTypeFromReaderAt() = %s, %v, want %s", test.name, got, err, test.want)
+		}
+		if position, _ := r.Seek(0, io.SeekCurrent); position != 0 {
+			t.Errorf("%s: position = %d, want 0", test.name, position)
+		}
+	}
+
+	if got, err := DetectBOMTypeFromReaderAt(errReaderAt{}); got != Unknown || err != iotest.ErrTimeout {
+		t.Errorf("DetectBOMTypeFromReaderAt() = %s, %v, want %s, %v", got, err, Unknown, iotest.ErrTimeout)
+	}
+}
+
+func TestDetectBOMTypeFromReadSeeker(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		want     BOMType
+		wantRest []byte
+	}{
+		{"empty", []byte{}, Unknown, []byte{}},
+		{"no bom", []byte("hello"), Unknown, []byte("hello")},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8, []byte("hello")},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00}, UTF16LE, []byte{'h', 0x00}},
+		{"bom only", []byte{0xEF, 0xBB, 0xBF}, UTF8, []byte{}},
+	}
+
+	for _, test := range tests {
+		r := bytes.NewReader(test.input)
+		// the detection starts from the beginning wherever r is
+		r.Seek(int64(len(test.input)), io.SeekStart)
+
+		got, err := DetectBOMTypeFromReadSeeker(r)
+		if err != nil || got != test.want {
+			t.Errorf("%s: DetectBOMTypeFromReadSeeker() = %s, %v, want %s", test.name, got, err, test.want)
+		}
+
+		rest, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(rest, test.wantRest) {
+			t.Errorf("%s: rest = %v, %v, want %v", test.name, rest, err, test.wantRest)
+		}
+	}
+}
+
+func TestSkipBOM(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		offset   int64
+		want     BOMType
+		wantSkip int64
+		wantRest []byte
+	}{
+		{"empty", []byte{}, 0, Unknown, 0, []byte{}},
+		{"no bom", []byte("hello"), 0, Unknown, 0, []byte("hello")},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), 0, UTF8, 3, []byte("hello")},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'h'}, 0, UTF32BE, 4, []byte{0x00, 0x00, 0x00, 'h'}},
+		{"offset", []byte("ab\xEF\xBB\xBFhello"), 2, UTF8, 3, []byte("hello")},
+		{"offset no bom", []byte("\xEF\xBB\xBFhello"), 3, Unknown, 0, []byte("hello")},
+	}
+
+	for _, test := range tests {
+		r := bytes.NewReader(test.input)
+		r.Seek(test.offset, io.SeekStart)
+
+		got, skip, err := SkipBOM(r)
+		if err != nil || got != test.want || skip != test.wantSkip {
+			t.Errorf("%s: SkipBOM() = %s, %d, %v, want %s, %d", test.name, got, skip, err, test.want, test.wantSkip)
+		}
+
+		rest, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(rest, test.wantRest) {
+			t.Errorf("%s: rest = %v, %v, want %v", test.name, rest, err, test.wantRest)
+		}
+	}
+}
+
+func TestDetectFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    BOMType
+	}{
+		{"utf8", []byte{0xEF, 0xBB, 0xBF, 'a'}, UTF8},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF}, UTF32BE},
+		{"no bom", []byte("hello"), Unknown},
+		{"empty", nil, Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := DetectFile(path)
+			if got != tt.want || err != nil {
+				t.Errorf("DetectFile() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+
+	if got, err := DetectFile(filepath.Join(dir, "missing")); got != Unknown || !os.IsNotExist(err) {
+		t.Errorf("DetectFile() = %s, %v, want %s and a not exist error", got, err, Unknown)
+	}
+}
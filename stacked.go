@@ -0,0 +1,81 @@
+package gobom
+
+import (
+	"bufio"
+	"io"
+)
+
+// CountLeadingBOMs returns the type of the BOM buffer starts with, and the
+// number of times it repeats, such as when BOM prefixed files were
+// concatenated. Only BOMs of the same type as the first one are counted.
+// If buffer does not start with a BOM, it returns Unknown and 0.
+func CountLeadingBOMs(buffer []byte) (BOMType, int) {
+	_, bomType, count := skipLeadingBOMs(buffer)
+	return bomType, count
+}
+
+// StripAllLeadingBOMs returns a sub slice of buffer that starts after all the
+// BOMs it starts with, and the type of them. As with CountLeadingBOMs, only
+// BOMs of the same type as the first one are removed.
+//
+// The returned slice shares the memory of buffer, nothing is copied.
+func StripAllLeadingBOMs(buffer []byte) ([]byte, BOMType) {
+	skip, bomType, _ := skipLeadingBOMs(buffer)
+	return buffer[skip:], bomType
+}
+
+// skipLeadingBOMs returns the number of bytes the leading BOMs of buffer
+// take, their type, and their count
+func skipLeadingBOMs(buffer []byte) (skip int, bomType BOMType, count int) {
+	for {
+		found, size := matchSignatureLen(signatures, buffer[skip:])
+		if found == Unknown || (count > 0 && found != bomType) {
+			return skip, bomType, count
+		}
+
+		bomType = found
+		skip += size
+		count++
+		if size == 0 {
+			// a BOM that cannot be removed ends the run
+			return skip, bomType, count
+		}
+	}
+}
+
+// stripAllReader removes all the leading BOMs of a reader
+type stripAllReader struct {
+	reader  *bufio.Reader
+	started bool
+}
+
+// NewStripAllBOMsReader returns a reader that removes all the BOMs r starts
+// with, in the same manner as StripAllLeadingBOMs.
+func NewStripAllBOMsReader(r io.Reader) io.Reader {
+	return &stripAllReader{reader: bufio.NewReader(r)}
+}
+
+// Read is an implementation of io.Reader interface
+func (s *stripAllReader) Read(buffer []byte) (int, error) {
+	if !s.started {
+		s.started = true
+
+		bomType := Unknown
+		for {
+			// an error here is returned by the reads that follow
+			head, _ := s.reader.Peek(maxBOMLen)
+			found, size := matchSignatureLen(signatures, head)
+			if found == Unknown || (bomType != Unknown && found != bomType) {
+				break
+			}
+
+			bomType = found
+			if size == 0 {
+				break
+			}
+			s.reader.Discard(size)
+		}
+	}
+
+	return s.reader.Read(buffer)
+}
@@ -0,0 +1,49 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStripAllLeadingBOMs(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		want      []byte
+		wantType  BOMType
+		wantCount int
+	}{
+		{"empty", nil, nil, Unknown, 0},
+		{"no bom", []byte("hello"), []byte("hello"), Unknown, 0},
+		{"single", []byte("\xEF\xBB\xBFhello"), []byte("hello"), UTF8, 1},
+		{"double", []byte("\xEF\xBB\xBF\xEF\xBB\xBFhello"), []byte("hello"), UTF8, 2},
+		{"only boms", []byte("\xEF\xBB\xBF\xEF\xBB\xBF\xEF\xBB\xBF"), []byte{}, UTF8, 3},
+		{"utf16le", []byte{0xFF, 0xFE, 0xFF, 0xFE, 'a', 0x00}, []byte{'a', 0x00}, UTF16LE, 2},
+		{"other type", []byte("\xEF\xBB\xBF\xFE\xFFa"), []byte("\xFE\xFFa"), UTF8, 1},
+		{"utf7 variant", []byte("+/v9AGE-"), []byte("+/v9AGE-"), UTF7, 1},
+	}
+
+	for _, test := range tests {
+		bomType, count := CountLeadingBOMs(test.input)
+		if bomType != test.wantType || count != test.wantCount {
+			t.Errorf("%s: CountLeadingBOMs() = %s, %d, want %s, %d",
+				test.name, bomType, count, test.wantType, test.wantCount)
+		}
+
+		got, bomType := StripAllLeadingBOMs(test.input)
+		if !bytes.Equal(got, test.want) || bomType != test.wantType {
+			t.Errorf("%s: StripAllLeadingBOMs() = %v, %s, want %v, %s",
+				test.name, got, bomType, test.want, test.wantType)
+		}
+
+		got, err := io.ReadAll(NewStripAllBOMsReader(iotest.OneByteReader(bytes.NewReader(test.input))))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("%s: NewStripAllBOMsReader() read %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package gobom
+
+import "io"
+
+// TeeDetector is an io.Reader that passes the content of the wrapped reader
+// unchanged, including the BOM, and records the BOM type on the way, for
+// proxies that must not modify the content but still log its encoding.
+type TeeDetector struct {
+	reader   io.Reader
+	detector IncrementalDetector
+	onDetect func(BOMType)
+	decided  bool
+}
+
+// NewTeeDetector creates a new TeeDetector on top of r. When onDetect is not
+// nil, it is called once, with the BOM type, by the Read that decided it.
+func NewTeeDetector(r io.Reader, onDetect func(BOMType)) *TeeDetector {
+	return &TeeDetector{reader: r, onDetect: onDetect}
+}
+
+// BOMType returns the BOM type of the content, and true once it was decided.
+// Until then it returns Unknown and false.
+func (t *TeeDetector) BOMType() (BOMType, bool) {
+	if !t.decided {
+		return Unknown, false
+	}
+
+	return t.detector.Finish(), true
+}
+
+// Read is an implementation of io.Reader interface. When the wrapped reader
+// ends before the BOM was decided, the BOM type is decided by the bytes that
+// were read, in the same manner as Reader.
+func (t *TeeDetector) Read(buffer []byte) (int, error) {
+	n, err := t.reader.Read(buffer)
+	if t.decided {
+		return n, err
+	}
+
+	if done, _ := t.detector.Feed(buffer[:n]); done || err == io.EOF {
+		t.decided = true
+		if bomType := t.detector.Finish(); t.onDetect != nil {
+			t.onDetect(bomType)
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,66 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTeeDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    BOMType
+	}{
+		{"utf8", []byte("\xEF\xBB\xBFhello"), UTF8},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0, 0, 0}, UTF32LE},
+		{"short utf16le", []byte{0xFF, 0xFE}, UTF16LE},
+		{"no bom", []byte("hello"), Unknown},
+		{"empty", nil, Unknown},
+	}
+
+	for _, tt := range tests {
+		for _, wrap := range []func(io.Reader) io.Reader{
+			func(r io.Reader) io.Reader { return r },
+			iotest.OneByteReader,
+			iotest.DataErrReader,
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				calls := 0
+				var detected BOMType
+				tee := NewTeeDetector(wrap(bytes.NewReader(tt.content)), func(bomType BOMType) {
+					calls++
+					detected = bomType
+				})
+
+				got, err := io.ReadAll(tee)
+				if err != nil || !bytes.Equal(got, tt.content) {
+					t.Errorf("got %q, %v, want %q", got, err, tt.content)
+				}
+				if calls != 1 || detected != tt.want {
+					t.Errorf("callback called %d times with %s, want once with %s", calls, detected, tt.want)
+				}
+				if bomType, ok := tee.BOMType(); !ok || bomType != tt.want {
+					t.Errorf("BOMType() = %s, %t, want %s, true", bomType, ok, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestTeeDetectorUndecided(t *testing.T) {
+	tee := NewTeeDetector(iotest.OneByteReader(bytes.NewReader([]byte{0xEF, 0xBB, 0xBF})), nil)
+
+	buffer := make([]byte, 1)
+	tee.Read(buffer)
+	if bomType, ok := tee.BOMType(); ok || bomType != Unknown {
+		t.Errorf("BOMType() = %s, %t, want %s, false", bomType, ok, Unknown)
+	}
+
+	tee.Read(buffer)
+	tee.Read(buffer)
+	if bomType, ok := tee.BOMType(); !ok || bomType != UTF8 {
+		t.Errorf("BOMType() = %s, %t, want %s, true", bomType, ok, UTF8)
+	}
+}
@@ -0,0 +1,111 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+	"golang.org/x/text/transform"
+)
+
+// encoderOf returns an encoder from UTF-8 to the encoding of t, that does not
+// write a BOM, or nil when t is not supported
+func encoderOf(t BOMType) *encoding.Encoder {
+	switch t {
+	case UTF8:
+		return unicode.UTF8.NewEncoder()
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder()
+	case UTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM).NewEncoder()
+	case UTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM).NewEncoder()
+	}
+
+	return nil
+}
+
+// bomRuneStripper removes a BOM char (U+FEFF) from the beginning of UTF-8
+// content. Unlike NewStripTransformer, it leaves the signatures of other BOM
+// types, which are valid UTF-8 text, such as "+/v8".
+type bomRuneStripper struct {
+	started bool
+}
+
+// Transform is an implementation of transform.Transformer interface
+func (b *bomRuneStripper) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	if !b.started {
+		if !atEOF && len(src) < len(UTF8Bom) && bytes.HasPrefix(UTF8Bom, src) {
+			return 0, 0, transform.ErrShortSrc
+		}
+		if bytes.HasPrefix(src, UTF8Bom) {
+			nSrc = len(UTF8Bom)
+		}
+		b.started = true
+	}
+
+	nDst = copy(dst, src[nSrc:])
+	nSrc += nDst
+	if nSrc < len(src) {
+		err = transform.ErrShortDst
+	}
+
+	return nDst, nSrc, err
+}
+
+// Reset is an implementation of transform.Transformer interface
+func (b *bomRuneStripper) Reset() {
+	b.started = false
+}
+
+// transcodingWriter encodes the UTF-8 content that is written to it, and
+// writes it to a Writer that adds the BOM
+type transcodingWriter struct {
+	encoder *transform.Writer
+	writer  *Writer
+}
+
+// NewTranscodingWriter returns a writer that takes UTF-8 content, and writes it
+// to w encoded as UTF-16 or UTF-32, as t tells, with the BOM of t at the
+// beginning, such as for exports that legacy Windows tools read. A BOM char
+// (U+FEFF) that the content starts with is not encoded again, and UTF-8 is
+// supported as well, for a UTF-8 BOM.
+//
+// A rune that is split between calls of Write is held until it is complete, so
+// Close must be called at the end of the content. It writes the held bytes,
+// and closes w when it implements io.Closer. As with NewWriter, the BOM is not
+// written for empty content. Invalid UTF-8 is encoded as U+FFFD.
+//
+// ErrUnsupportedEncoding is returned for any other BOM type, including
+// Unknown.
+func NewTranscodingWriter(w io.Writer, t BOMType) (io.WriteCloser, error) {
+	encoder := encoderOf(t)
+	if encoder == nil {
+		return nil, ErrUnsupportedEncoding
+	}
+
+	writer := NewWriter(w, t)
+	return &transcodingWriter{
+		encoder: transform.NewWriter(writer, transform.Chain(&bomRuneStripper{}, encoder)),
+		writer:  writer,
+	}, nil
+}
+
+// Write is an implementation of io.Writer interface
+func (t *transcodingWriter) Write(p []byte) (int, error) {
+	return t.encoder.Write(p)
+}
+
+// Close is an implementation of io.Closer interface
+func (t *transcodingWriter) Close() error {
+	err := t.encoder.Close()
+	if closeErr := t.writer.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
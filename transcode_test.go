@@ -0,0 +1,57 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTranscodingWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		bom    BOMType
+		writes []string
+		want   []byte
+	}{
+		{"utf16le", UTF16LE, []string{"hi"}, []byte{0xFF, 0xFE, 'h', 0, 'i', 0}},
+		{"utf16be", UTF16BE, []string{"hi"}, []byte{0xFE, 0xFF, 0, 'h', 0, 'i'}},
+		{"utf32le", UTF32LE, []string{"h"}, []byte{0xFF, 0xFE, 0, 0, 'h', 0, 0, 0}},
+		{"utf32be", UTF32BE, []string{"h"}, []byte{0, 0, 0xFE, 0xFF, 0, 0, 0, 'h'}},
+		{"utf8", UTF8, []string{"hi"}, []byte("\xEF\xBB\xBFhi")},
+		{"split rune", UTF16LE, []string{"\xC3", "\xA9"}, []byte{0xFF, 0xFE, 0xE9, 0}},
+		{"split surrogate pair", UTF16BE, []string{"\xF0\x9F", "\x98\x80"}, []byte{0xFE, 0xFF, 0xD8, 0x3D, 0xDE, 0x00}},
+		{"existing bom", UTF16LE, []string{"\xEF\xBB", "\xBFa"}, []byte{0xFF, 0xFE, 'a', 0}},
+		{"invalid utf8", UTF16LE, []string{"\xFF"}, []byte{0xFF, 0xFE, 0xFD, 0xFF}},
+		{"utf7 signature", UTF16LE, []string{"+/v8 a"}, mustEncode(t, "+/v8 a", UTF16LE)},
+		{"scsu signature", UTF16LE, []string{"\x0E\xFE\xFFa"}, mustEncode(t, "\x0E\uFFFD\uFFFDa", UTF16LE)},
+		{"empty", UTF16LE, nil, []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w, err := NewTranscodingWriter(&out, tt.bom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Errorf("Write(%q) = %d, %v, want %d", s, n, err, len(s))
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tt.want) {
+				t.Errorf("got % X, want % X", out.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTranscodingWriterUnsupported(t *testing.T) {
+	for _, bomType := range []BOMType{Unknown, GB18030, UTF7} {
+		if _, err := NewTranscodingWriter(&bytes.Buffer{}, bomType); err != ErrUnsupportedEncoding {
+			t.Errorf("NewTranscodingWriter(%s) error = %v, want %v", bomType, err, ErrUnsupportedEncoding)
+		}
+	}
+}
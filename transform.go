@@ -0,0 +1,55 @@
+package gobom
+
+import "golang.org/x/text/transform"
+
+// bomTransformer removes the BOM at the beginning of the content, and places
+// add there instead
+type bomTransformer struct {
+	add     []byte
+	started bool
+}
+
+// NewStripTransformer returns a transform.Transformer that removes the BOM at
+// the beginning of the content, if any, so it can be chained with other
+// transformers, such as decoders and normalizers.
+func NewStripTransformer() transform.Transformer {
+	return &bomTransformer{}
+}
+
+// NewAddBOMTransformer returns a transform.Transformer that makes the content
+// start with the BOM of t: a BOM the content already starts with is replaced,
+// so there is never more than one. If t is Unknown, it removes the BOM as
+// NewStripTransformer does.
+func NewAddBOMTransformer(t BOMType) transform.Transformer {
+	return &bomTransformer{add: signatureOf(t)}
+}
+
+// Transform is an implementation of transform.Transformer interface
+func (b *bomTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	if !b.started {
+		if !atEOF && !bomDecided(src) {
+			return 0, 0, transform.ErrShortSrc
+		}
+		if len(dst) < len(b.add) {
+			return 0, 0, transform.ErrShortDst
+		}
+
+		_, nSrc = matchSignatureLen(signatures, src)
+		nDst = copy(dst, b.add)
+		b.started = true
+	}
+
+	n := copy(dst[nDst:], src[nSrc:])
+	nDst += n
+	nSrc += n
+	if nSrc < len(src) {
+		err = transform.ErrShortDst
+	}
+
+	return nDst, nSrc, err
+}
+
+// Reset is an implementation of transform.Transformer interface
+func (b *bomTransformer) Reset() {
+	b.started = false
+}
@@ -0,0 +1,69 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+func TestStripTransformer(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"empty", []byte{}, []byte{}},
+		{"no bom", []byte("hello"), []byte("hello")},
+		{"utf8", []byte("\xEF\xBB\xBFhello"), []byte("hello")},
+		{"bom only", []byte{0xFF, 0xFE}, []byte{}},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, []byte{'h', 0x00, 0x00, 0x00}},
+		{"second bom kept", []byte("\xEF\xBB\xBF\xEF\xBB\xBFhi"), []byte("\xEF\xBB\xBFhi")},
+	}
+
+	for _, test := range tests {
+		got, _, err := transform.Bytes(NewStripTransformer(), test.input)
+		if err != nil || !bytes.Equal(got, test.want) {
+			t.Errorf("%s: transform.Bytes() = %v, %v, want %v", test.name, got, err, test.want)
+		}
+
+		reader := transform.NewReader(iotest.OneByteReader(bytes.NewReader(test.input)), NewStripTransformer())
+		got, err = io.ReadAll(reader)
+		if err != nil || !bytes.Equal(got, test.want) {
+			t.Errorf("%s: transform.NewReader() = %v, %v, want %v", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestAddBOMTransformer(t *testing.T) {
+	tests := []struct {
+		name    string
+		bomType BOMType
+		input   []byte
+		want    []byte
+	}{
+		{"empty", UTF8, []byte{}, []byte("\xEF\xBB\xBF")},
+		{"no bom", UTF8, []byte("hello"), []byte("\xEF\xBB\xBFhello")},
+		{"same bom", UTF8, []byte("\xEF\xBB\xBFhello"), []byte("\xEF\xBB\xBFhello")},
+		{"other bom", UTF16LE, []byte{0xFE, 0xFF, 'h', 0x00}, []byte{0xFF, 0xFE, 'h', 0x00}},
+		{"unknown", Unknown, []byte("\xEF\xBB\xBFhello"), []byte("hello")},
+	}
+
+	for _, test := range tests {
+		transformer := NewAddBOMTransformer(test.bomType)
+		for i := 0; i < 2; i++ {
+			got, _, err := transform.Bytes(transformer, test.input)
+			if err != nil || !bytes.Equal(got, test.want) {
+				t.Errorf("%s: transform.Bytes() = %v, %v, want %v", test.name, got, err, test.want)
+			}
+		}
+	}
+
+	chain := transform.Chain(NewStripTransformer(), NewAddBOMTransformer(UTF8))
+	got, _, err := transform.String(chain, "\xEF\xBB\xBFhello")
+	if err != nil || got != "\xEF\xBB\xBFhello" {
+		t.Errorf("transform.Chain() = %q, %v, want %q", got, err, "\xEF\xBB\xBFhello")
+	}
+}
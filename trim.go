@@ -0,0 +1,60 @@
+package gobom
+
+// TrimBOM returns a sub slice of b that starts after the BOM, and the type of
+// the BOM that was removed. If b does not start with a BOM, b is returned as is
+// with Unknown. A UTF-7 BOM that cannot be removed (see UTF7BomVariants) is
+// reported, but b is returned as is.
+//
+// The returned slice shares the memory of b, nothing is copied.
+func TrimBOM(b []byte) ([]byte, BOMType) {
+	bomType, size := matchSignatureLen(signatures, b)
+	return b[size:], bomType
+}
+
+// TrimBOMString returns s without its leading BOM, and the type of the BOM that
+// was removed. If s does not start with a BOM, s is returned as is with
+// Unknown. As with TrimBOM, a UTF-7 BOM that cannot be removed is reported, but
+// s is returned as is.
+//
+// The returned string shares the memory of s, nothing is copied.
+func TrimBOMString(s string) (string, BOMType) {
+	bomType, size := matchSignatureLen(signatures, s)
+	return s[size:], bomType
+}
+
+// StartsWithBOMRune returns true if the first rune of runes is the BOM rune
+// (U+FEFF).
+func StartsWithBOMRune(runes []rune) bool {
+	return len(runes) > 0 && IsBOMRune(runes[0])
+}
+
+// TrimBOMRunes returns runes without a leading BOM rune (U+FEFF), and true if
+// it was removed. After the content was decoded, this is what is left of the
+// BOM.
+//
+// The returned slice shares the memory of runes, nothing is copied.
+func TrimBOMRunes(runes []rune) ([]rune, bool) {
+	if !StartsWithBOMRune(runes) {
+		return runes, false
+	}
+
+	return runes[1:], true
+}
+
+// StripBOMInPlace removes the BOM from b by moving the content that follows it
+// to the beginning of b, and returns b shortened by the size of the BOM. If b
+// does not start with a BOM, b is returned as is. Nothing is allocated.
+//
+// The returned slice uses the same underlying array as b, and starts at the
+// same address, so b must not be used after the call. It is useful when b
+// belongs to a pool, and must keep its start. When moving the content is not
+// needed, TrimBOM only re-slices b, and is cheaper.
+func StripBOMInPlace(b []byte) []byte {
+	_, size := matchSignatureLen(signatures, b)
+	if size == 0 {
+		return b
+	}
+
+	n := copy(b, b[size:])
+	return b[:n]
+}
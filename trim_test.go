@@ -0,0 +1,114 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTrimBOM(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		want     []byte
+		wantType BOMType
+	}{
+		{nil, nil, Unknown},
+		{[]byte("hello"), []byte("hello"), Unknown},
+		{[]byte{0xEF, 0xBB, 0xBF}, []byte{}, UTF8},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a'}, []byte("a"), UTF8},
+		{[]byte{0xFF, 0xFE, 'a', 0x00}, []byte{'a', 0x00}, UTF16LE},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00, 'a'}, []byte{'a'}, UTF32LE},
+	}
+
+	for _, test := range tests {
+		got, gotType := TrimBOM(test.input)
+		if !bytes.Equal(got, test.want) || gotType != test.wantType {
+			t.Errorf("TrimBOM(%v) = %v, %s, want %v, %s",
+				test.input, got, gotType, test.want, test.wantType)
+		}
+	}
+}
+
+func TestTrimBOMString(t *testing.T) {
+	tests := []struct {
+		input    string
+		want     string
+		wantType BOMType
+	}{
+		{"", "", Unknown},
+		{"hello", "hello", Unknown},
+		{"\xEF\xBB\xBF", "", UTF8},
+		{"\xEF\xBB\xBFhello", "hello", UTF8},
+		{"\xFE\xFF\x00a", "\x00a", UTF16BE},
+		{"\x00\x00\xFE\xFF\x00\x00\x00a", "\x00\x00\x00a", UTF32BE},
+		{"+/v8-hello", "hello", UTF7},
+		{"+/v9", "+/v9", UTF7},
+		{"+/v9AGE-", "+/v9AGE-", UTF7},
+	}
+
+	for _, test := range tests {
+		got, gotType := TrimBOMString(test.input)
+		if got != test.want || gotType != test.wantType {
+			t.Errorf("TrimBOMString(%q) = %q, %s, want %q, %s",
+				test.input, got, gotType, test.want, test.wantType)
+		}
+	}
+}
+
+func TestTrimBOMRunes(t *testing.T) {
+	tests := []struct {
+		input   []rune
+		want    []rune
+		trimmed bool
+	}{
+		{nil, nil, false},
+		{[]rune("hello"), []rune("hello"), false},
+		{[]rune{BOMRune}, []rune{}, true},
+		{[]rune("\uFEFFhello"), []rune("hello"), true},
+		{[]rune("h\uFEFFello"), []rune("h\uFEFFello"), false},
+	}
+
+	for _, test := range tests {
+		if got := StartsWithBOMRune(test.input); got != test.trimmed {
+			t.Errorf("StartsWithBOMRune(%q) = %t, want %t", test.input, got, test.trimmed)
+		}
+
+		got, trimmed := TrimBOMRunes(test.input)
+		if string(got) != string(test.want) || trimmed != test.trimmed {
+			t.Errorf("TrimBOMRunes(%q) = %q, %t, want %q, %t",
+				test.input, got, trimmed, test.want, test.trimmed)
+		}
+	}
+}
+
+func TestStripBOMInPlace(t *testing.T) {
+	tests := []struct {
+		input []byte
+		want  []byte
+	}{
+		{nil, nil},
+		{[]byte("hello"), []byte("hello")},
+		{[]byte{0xEF, 0xBB, 0xBF}, []byte{}},
+		{[]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}, []byte("ab")},
+		{[]byte{0xFF, 0xFE, 0x00, 0x00, 'a'}, []byte("a")},
+	}
+
+	for _, test := range tests {
+		input := append([]byte(nil), test.input...)
+		got := StripBOMInPlace(input)
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("StripBOMInPlace(%v) = %v, want %v", test.input, got, test.want)
+		}
+		if len(got) > 0 && &got[0] != &input[0] {
+			t.Errorf("StripBOMInPlace(%v) did not keep the start of the buffer", test.input)
+		}
+	}
+
+	buffer := []byte{0xEF, 0xBB, 0xBF, 'a', 'b', 'c'}
+	allocs := testing.AllocsPerRun(100, func() {
+		buffer[0], buffer[1], buffer[2] = 0xEF, 0xBB, 0xBF
+		StripBOMInPlace(buffer)
+	})
+	if allocs != 0 {
+		t.Errorf("StripBOMInPlace allocated %f times", allocs)
+	}
+}
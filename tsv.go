@@ -0,0 +1,42 @@
+package gobom
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// TSVWriter is a csv.Writer that writes tab separated records as UTF-16LE with
+// a BOM and CRLF line endings, the format Excel imports reliably besides UTF-8
+// CSV with a BOM
+type TSVWriter struct {
+	*csv.Writer
+	encoder io.WriteCloser
+}
+
+// NewTSVWriter creates a new TSVWriter on top of w. The records are given as
+// UTF-8, and are encoded as UTF-16LE on the fly. Line endings are CRLF,
+// including those inside quoted fields.
+//
+// Close must be called at the end, in order to write the buffered records.
+func NewTSVWriter(w io.Writer) *TSVWriter {
+	// UTF16LE is always supported
+	encoder, _ := NewTranscodingWriter(w, UTF16LE)
+
+	writer := csv.NewWriter(encoder)
+	writer.Comma = '\t'
+	writer.UseCRLF = true
+
+	return &TSVWriter{Writer: writer, encoder: encoder}
+}
+
+// Close is an implementation of io.Closer interface. It flushes the buffered
+// records, and closes w when it implements io.Closer.
+func (t *TSVWriter) Close() error {
+	t.Writer.Flush()
+	err := t.Writer.Error()
+	if closeErr := t.encoder.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTSVWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewTSVWriter(&out)
+
+	records := [][]string{
+		{"name", "note"},
+		{"café", "two\nlines"},
+	}
+	if err := w.WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, bomType, err := DecodeToString(out.Bytes())
+	if err != nil || bomType != UTF16LE {
+		t.Fatalf("DecodeToString() = %s, %v, want %s", bomType, err, UTF16LE)
+	}
+
+	want := "name\tnote\r\ncafé\t\"two\r\nlines\"\r\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTSVWriterEmpty(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewTSVWriter(&out).Close(); err != nil || out.Len() != 0 {
+		t.Errorf("Close() = %v, wrote % X, want nothing", err, out.Bytes())
+	}
+}
@@ -0,0 +1,84 @@
+package gobom
+
+// UTF16Verdict is the result of verifying UTF-16 content
+type UTF16Verdict struct {
+	// Type is the BOM type of the content, UTF16LE or UTF16BE
+	Type BOMType
+	// Units is the number of code units that were examined
+	Units int
+	// UnpairedSurrogates is the number of surrogates without their pair
+	UnpairedSurrogates int
+	// InvalidUnits is the number of code units that must not be found in
+	// text: the noncharacters U+FFFE and U+FFFF, and an odd byte at the end
+	// of the content
+	InvalidUnits int
+	// FirstInvalid is the offset in the buffer of the first problem that was
+	// found, or -1 if there is none
+	FirstInvalid int
+}
+
+// Consistent checks if no problems were found in the content
+func (v UTF16Verdict) Consistent() bool {
+	return v.UnpairedSurrogates == 0 && v.InvalidUnits == 0
+}
+
+// VerifyUTF16 scans the first 8 KiB of buffer after its UTF-16 BOM for
+// unpaired surrogates and invalid code units, so corrupt content can be
+// rejected before it is decoded.
+//
+// ErrNoBOM is returned if buffer does not start with a UTF-16 BOM. A
+// surrogate pair or a code unit that were cut by the end of the sample are not
+// considered to be invalid.
+func VerifyUTF16(buffer []byte) (UTF16Verdict, error) {
+	bomType, size := matchSignatureLen(signatures, buffer)
+	if bomType != UTF16LE && bomType != UTF16BE {
+		return UTF16Verdict{FirstInvalid: -1}, ErrNoBOM
+	}
+
+	order, _ := bomType.ByteOrder()
+	content := sample(buffer[size:], sampleSize)
+	verdict := UTF16Verdict{Type: bomType, FirstInvalid: -1}
+
+	invalid := func(offset int) {
+		if verdict.FirstInvalid < 0 {
+			verdict.FirstInvalid = size + offset
+		}
+	}
+
+	// when the sample is the whole content, nothing was cut by its end
+	whole := len(content) == len(buffer)-size
+
+	for i := 0; i+1 < len(content); i += 2 {
+		verdict.Units++
+		unit := order.Uint16(content[i:])
+
+		switch {
+		case unit >= 0xD800 && unit < 0xDC00:
+			if i+3 >= len(content) {
+				if !whole {
+					// the pair was cut by the end of the sample
+					continue
+				}
+			} else if next := order.Uint16(content[i+2:]); next >= 0xDC00 && next < 0xE000 {
+				verdict.Units++
+				i += 2
+				continue
+			}
+			verdict.UnpairedSurrogates++
+			invalid(i)
+		case unit >= 0xDC00 && unit < 0xE000:
+			verdict.UnpairedSurrogates++
+			invalid(i)
+		case unit == 0xFFFE || unit == 0xFFFF:
+			verdict.InvalidUnits++
+			invalid(i)
+		}
+	}
+
+	if len(content)%2 != 0 && whole {
+		verdict.InvalidUnits++
+		invalid(len(content) - 1)
+	}
+
+	return verdict, nil
+}
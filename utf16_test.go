@@ -0,0 +1,70 @@
+package gobom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVerifyUTF16(t *testing.T) {
+	tests := []struct {
+		name         string
+		buffer       []byte
+		wantUnits    int
+		wantUnpaired int
+		wantInvalid  int
+		wantFirst    int
+		wantErr      error
+	}{
+		{"no bom", []byte("hello"), 0, 0, 0, -1, ErrNoBOM},
+		{"utf8 bom", []byte("\xEF\xBB\xBFhello"), 0, 0, 0, -1, ErrNoBOM},
+		{"empty", []byte{0xFF, 0xFE}, 0, 0, 0, -1, nil},
+		{"le text", []byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}, 2, 0, 0, -1, nil},
+		{"be text", []byte{0xFE, 0xFF, 0x00, 'h', 0x00, 'i'}, 2, 0, 0, -1, nil},
+		{"le pair", []byte{0xFF, 0xFE, 0x3D, 0xD8, 0x00, 0xDE}, 2, 0, 0, -1, nil},
+		{"be pair", []byte{0xFE, 0xFF, 0xD8, 0x3D, 0xDE, 0x00}, 2, 0, 0, -1, nil},
+		{"le high at end", []byte{0xFF, 0xFE, 'h', 0x00, 0x3D, 0xD8}, 2, 1, 0, 4, nil},
+		{"be high at end", []byte{0xFE, 0xFF, 0xD8, 0x3D}, 1, 1, 0, 2, nil},
+		{"le lone high", []byte{0xFF, 0xFE, 0x3D, 0xD8, 'h', 0x00}, 2, 1, 0, 2, nil},
+		{"le lone low", []byte{0xFF, 0xFE, 'h', 0x00, 0x00, 0xDE}, 2, 1, 0, 4, nil},
+		{"be noncharacter", []byte{0xFE, 0xFF, 0x00, 'h', 0xFF, 0xFF}, 2, 0, 1, 4, nil},
+		{"le odd byte", []byte{0xFF, 0xFE, 'h', 0x00, 'i'}, 1, 0, 1, 4, nil},
+	}
+
+	for _, test := range tests {
+		verdict, err := VerifyUTF16(test.buffer)
+		if err != test.wantErr {
+			t.Errorf("%s: VerifyUTF16() error = %v, want %v", test.name, err, test.wantErr)
+			continue
+		}
+
+		if verdict.Units != test.wantUnits || verdict.UnpairedSurrogates != test.wantUnpaired ||
+			verdict.InvalidUnits != test.wantInvalid || verdict.FirstInvalid != test.wantFirst {
+			t.Errorf("%s: VerifyUTF16() = %+v, want units %d, unpaired %d, invalid %d, first %d",
+				test.name, verdict, test.wantUnits, test.wantUnpaired, test.wantInvalid, test.wantFirst)
+		}
+
+		consistent := test.wantUnpaired == 0 && test.wantInvalid == 0
+		if verdict.Consistent() != consistent {
+			t.Errorf("%s: Consistent() = %t, want %t", test.name, verdict.Consistent(), consistent)
+		}
+	}
+}
+
+func TestVerifyUTF16Sample(t *testing.T) {
+	buffer := append([]byte{0xFF, 0xFE}, bytes.Repeat([]byte{'a', 0x00}, sampleSize)...)
+	buffer = append(buffer, 0x00, 0xDE)
+
+	verdict, err := VerifyUTF16(buffer)
+	if err != nil || !verdict.Consistent() || verdict.Units != sampleSize/2 {
+		t.Errorf("VerifyUTF16() = %+v, %v, want consistent %d units", verdict, err, sampleSize/2)
+	}
+
+	// a pair that was cut by the end of the sample
+	buffer = append([]byte{0xFF, 0xFE}, bytes.Repeat([]byte{'a', 0x00}, sampleSize/2-1)...)
+	buffer = append(buffer, 0x3D, 0xD8, 0x00, 0xDE)
+
+	verdict, err = VerifyUTF16(buffer)
+	if err != nil || !verdict.Consistent() {
+		t.Errorf("VerifyUTF16() = %+v, %v, want consistent", verdict, err)
+	}
+}
@@ -0,0 +1,82 @@
+package gobom
+
+import (
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+	"golang.org/x/text/transform"
+)
+
+// decoderOf returns a decoder from the encoding of t to UTF-8, or nil when the
+// content of t is used as is
+func decoderOf(t BOMType) *encoding.Decoder {
+	switch t {
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	case UTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM).NewDecoder()
+	case UTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM).NewDecoder()
+	case GB18030:
+		return simplifiedchinese.GB18030.NewDecoder()
+	}
+
+	return nil
+}
+
+// utf8Reader decodes the content of a Reader to UTF-8
+type utf8Reader struct {
+	source *Reader
+	reader io.Reader
+}
+
+// NewUTF8Reader returns a reader that removes the BOM of r, and decodes UTF-16,
+// UTF-32 and GB18030 content to UTF-8 on the fly, so the caller always gets
+// UTF-8 without a BOM. Content with a UTF-8 BOM or without a BOM is returned
+// as is, and so is the content of any other BOM type, without its BOM.
+//
+// The detection happens on the first call to Read. Invalid content, such as
+// an unpaired surrogate, is replaced by U+FFFD.
+func NewUTF8Reader(r io.Reader) io.Reader {
+	return &utf8Reader{source: NewReader(r)}
+}
+
+// Read is an implementation of io.Reader interface
+func (u *utf8Reader) Read(buffer []byte) (int, error) {
+	if u.reader == nil {
+		u.reader = u.source
+		if decoder := decoderOf(u.source.BOMType()); decoder != nil {
+			u.reader = transform.NewReader(u.source, decoder)
+		}
+	}
+
+	return u.reader.Read(buffer)
+}
+
+// EnsureUTF8NoBOM returns a reader of the content of r as UTF-8 without a BOM,
+// the canonical form most code expects. It is the same as NewUTF8Reader, and
+// is named after the result, for code that normalizes its input.
+func EnsureUTF8NoBOM(r io.Reader) io.Reader {
+	return NewUTF8Reader(r)
+}
+
+// EnsureUTF8NoBOMBytes returns the content of b as UTF-8 without a BOM, as
+// EnsureUTF8NoBOM does. When b is UTF-8 already, a sub slice of it is
+// returned, without copying.
+func EnsureUTF8NoBOMBytes(b []byte) []byte {
+	content, bomType := TrimBOM(b)
+
+	decoder := decoderOf(bomType)
+	if decoder == nil {
+		return content
+	}
+
+	// a decoder that replaces invalid content does not fail
+	decoded, _ := decoder.Bytes(content)
+	return decoded
+}
@@ -0,0 +1,69 @@
+package gobom
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewUTF8Reader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"empty", []byte{}, ""},
+		{"no bom", []byte("naïve"), "naïve"},
+		{"utf8", []byte("\xEF\xBB\xBFnaïve"), "naïve"},
+		{"utf16le", []byte{0xFF, 0xFE, 'h', 0x00, 0xAC, 0x20, 0x3D, 0xD8, 0x00, 0xDE}, "h€\U0001F600"},
+		{"utf16be", []byte{0xFE, 0xFF, 0x00, 'h', 0x20, 0xAC}, "h€"},
+		{"utf16le unpaired", []byte{0xFF, 0xFE, 0x3D, 0xD8, 'h', 0x00}, "\uFFFDh"},
+		{"utf32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00, 0x00, 0xF6, 0x01, 0x00}, "h\U0001F600"},
+		{"utf32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x20, 0xAC}, "€"},
+		{"gb18030", []byte{0x84, 0x31, 0x95, 0x33, 'h', 0xD6, 0xD0, 0xA2, 0xE3}, "h中€"},
+	}
+
+	for _, test := range tests {
+		got, err := io.ReadAll(NewUTF8Reader(iotest.OneByteReader(bytes.NewReader(test.input))))
+		if err != nil || string(got) != test.want {
+			t.Errorf("%s: got %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+
+	source := iotest.TimeoutReader(bytes.NewReader([]byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}))
+	if _, err := io.ReadAll(NewUTF8Reader(source)); err != iotest.ErrTimeout {
+		t.Errorf("error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestEnsureUTF8NoBOM(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"utf8 bom", []byte("\xEF\xBB\xBFcafé"), "café"},
+		{"no bom", []byte("café"), "café"},
+		{"utf16le", mustEncode(t, "café", UTF16LE), "café"},
+		{"utf16be", mustEncode(t, "café", UTF16BE), "café"},
+		{"utf32le", mustEncode(t, "café", UTF32LE), "café"},
+		{"gb18030", []byte{0x84, 0x31, 0x95, 0x33, 'c', 'a', 'f', 0xA8, 0xA6}, "café"},
+		{"unpaired surrogate", []byte{0xFF, 0xFE, 0x00, 0xD8, 'a', 0x00}, "�a"},
+		{"odd length", []byte{0xFF, 0xFE, 'a', 0x00, 'b'}, "a�"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnsureUTF8NoBOMBytes(tt.input); string(got) != tt.want {
+				t.Errorf("EnsureUTF8NoBOMBytes() = %q, want %q", got, tt.want)
+			}
+
+			got, err := io.ReadAll(EnsureUTF8NoBOM(bytes.NewReader(tt.input)))
+			if err != nil || string(got) != tt.want {
+				t.Errorf("EnsureUTF8NoBOM() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package gobom
+
+import "unicode/utf8"
+
+// utf8Validator validates UTF-8 content that is given to it in chunks
+type utf8Validator struct {
+	// offset is the offset of pending at the content
+	offset int64
+	// pending is the beginning of a sequence that was cut by the end of the
+	// last chunk
+	pending []byte
+}
+
+// check validates chunk, and returns the number of bytes at its beginning
+// that are valid, with an *InvalidUTF8Error when an invalid sequence was found.
+func (v *utf8Validator) check(chunk []byte) (int, error) {
+	data := chunk
+	if len(v.pending) > 0 {
+		data = append(v.pending[:len(v.pending):len(v.pending)], chunk...)
+	}
+	// the pending bytes were already validated as far as they could be
+	returned := len(data) - len(chunk)
+
+	i := 0
+	for i < len(data) {
+		if data[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			break
+		}
+
+		ch, size := utf8.DecodeRune(data[i:])
+		if ch == utf8.RuneError && size == 1 {
+			valid := i - returned
+			if valid < 0 {
+				valid = 0
+			}
+			return valid, &InvalidUTF8Error{Offset: v.offset + int64(i)}
+		}
+		i += size
+	}
+
+	v.offset += int64(i)
+	v.pending = append(v.pending[:0], data[i:]...)
+
+	return len(chunk), nil
+}
+
+// finish returns an *InvalidUTF8Error when the content ended in the middle of
+// a sequence
+func (v *utf8Validator) finish() error {
+	if len(v.pending) > 0 {
+		return &InvalidUTF8Error{Offset: v.offset}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderValidateUTF8(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		want       []byte
+		wantOffset int64
+	}{
+		{"empty", []byte{}, []byte{}, -1},
+		{"ascii", []byte("hello"), []byte("hello"), -1},
+		{"utf8 bom", []byte("\xEF\xBB\xBFnaïve €"), []byte("naïve €"), -1},
+		{"invalid", []byte("abc\xFFdef"), []byte("abc"), 3},
+		{"invalid after bom", []byte("\xEF\xBB\xBFab\xC3\x28"), []byte("ab"), 2},
+		{"cut at end", []byte("ab\xE2\x82"), []byte("ab\xE2\x82"), 2},
+		{"utf16 not validated", []byte{0xFF, 0xFE, 0xFF, 0xFF}, []byte{0xFF, 0xFF}, -1},
+	}
+
+	for _, test := range tests {
+		readers := map[string]io.Reader{
+			"plain":    bytes.NewReader(test.input),
+			"one byte": iotest.OneByteReader(bytes.NewReader(test.input)),
+		}
+		for kind, source := range readers {
+			got, err := io.ReadAll(NewReader(source, WithValidateUTF8()))
+
+			var invalid *InvalidUTF8Error
+			switch {
+			case test.wantOffset < 0 && err != nil:
+				t.Errorf("%s/%s: unexpected error: %v", test.name, kind, err)
+			case test.wantOffset >= 0 && (!errors.As(err, &invalid) || invalid.Offset != test.wantOffset):
+				t.Errorf("%s/%s: error = %v, want invalid UTF-8 at offset %d", test.name, kind, err, test.wantOffset)
+			}
+			// a sequence that was cut between reads is returned until it is
+			// known to be invalid
+			exact := kind == "plain" || test.wantOffset < 0
+			if (exact && !bytes.Equal(got, test.want)) || !bytes.HasPrefix(got, test.want) {
+				t.Errorf("%s/%s: got %q, want %q", test.name, kind, got, test.want)
+			}
+		}
+	}
+}
+
+func TestReaderValidateUTF8Runes(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte("aé\xFF")), WithValidateUTF8())
+	for _, want := range []rune{'a', 'é'} {
+		if ch, _, err := reader.ReadRune(); ch != want || err != nil {
+			t.Errorf("ReadRune() = %q, %v, want %q", ch, err, want)
+		}
+	}
+
+	var invalid *InvalidUTF8Error
+	if _, _, err := reader.ReadRune(); !errors.As(err, &invalid) || invalid.Offset != 3 {
+		t.Errorf("ReadRune() error = %v, want invalid UTF-8 at offset 3", err)
+	}
+	if _, err := reader.ReadByte(); !errors.As(err, &invalid) {
+		t.Errorf("ReadByte() error = %v, want invalid UTF-8", err)
+	}
+
+	var buffer bytes.Buffer
+	reader = NewReader(bytes.NewReader([]byte("ab\xFF")), WithValidateUTF8())
+	if _, err := reader.WriteTo(&buffer); !errors.As(err, &invalid) || buffer.String() != "ab" {
+		t.Errorf("WriteTo() = %q, %v, want %q with invalid UTF-8", buffer.String(), err, "ab")
+	}
+}
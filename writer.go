@@ -0,0 +1,262 @@
+package gobom
+
+import "io"
+
+// Writer is an implementation for the io.Writer that controls the BOM at the
+// beginning of the content that is written to the wrapped writer
+type Writer struct {
+	writer  io.Writer
+	pending []byte
+	strip   bool
+	decided bool
+	header  [maxBOMLen]byte
+	held    int
+	reject  bool
+	err     error
+}
+
+// WriterOption configures a Writer
+type WriterOption func(*Writer)
+
+// WithRejectExistingBOM makes a Writer that writes a BOM fail with an
+// *ExistingBOMError when the content already starts with a BOM, instead of
+// writing two BOMs or replacing it, so the double BOM bug is caught where the
+// content is produced. Nothing is written then, and all the following calls
+// return the same error.
+//
+// As with NewStripWriter, Flush or Close must be called when the content might
+// be shorter than a BOM. It has no effect on a Writer that does not write a
+// BOM.
+func WithRejectExistingBOM() WriterOption {
+	return func(w *Writer) {
+		w.reject = true
+	}
+}
+
+// newWriter creates a new Writer on top of w, that writes bom, removes the BOM
+// of the content when strip is set, and configures it with opts
+func newWriter(w io.Writer, bom []byte, strip bool, opts []WriterOption) *Writer {
+	writer := &Writer{writer: w, pending: bom, strip: strip}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	writer.reject = writer.reject && len(bom) > 0
+
+	return writer
+}
+
+// NewWriter creates a new Writer on top of w, and configures it with opts, that
+// writes the BOM of t once, right before the first byte that is written to it,
+// such as for UTF-8 CSV files that Excel opens correctly. When nothing is
+// written, neither is the BOM. If t is Unknown, the content is written as is.
+func NewWriter(w io.Writer, t BOMType, opts ...WriterOption) *Writer {
+	return newWriter(w, signatureOf(t), false, opts)
+}
+
+// NewStripWriter creates a new Writer on top of w, that removes a BOM from the
+// beginning of the content that is written to it, even when the BOM is split
+// between calls of Write, so sinks that must never hold a BOM, such as hashes
+// and canonical storage, are protected.
+//
+// The first bytes are held until they decide the BOM, so Flush or Close must
+// be called when the content might be shorter than a BOM.
+func NewStripWriter(w io.Writer) *Writer {
+	return newWriter(w, nil, true, nil)
+}
+
+// BOMPolicy controls what a Writer does with the BOM of the content
+type BOMPolicy uint8
+
+// Enumeration of the BOM policies
+const (
+	// BOMPreserve writes the content as is, with or without a BOM
+	BOMPreserve BOMPolicy = iota
+	// BOMAlways makes the content start with the chosen BOM, which replaces
+	// the BOM the content already starts with, if any
+	BOMAlways
+	// BOMNever removes the BOM the content starts with, if any
+	BOMNever
+)
+
+// NewPolicyWriter creates a new Writer on top of w, and configures it with
+// opts, that handles the BOM of the content according to policy, where t is
+// the BOM that BOMAlways writes. With BOMAlways and Unknown as t, the Writer
+// acts as with BOMNever.
+//
+// As with NewStripWriter, Flush or Close must be called when the policy is not
+// BOMPreserve.
+func NewPolicyWriter(w io.Writer, policy BOMPolicy, t BOMType, opts ...WriterOption) *Writer {
+	switch policy {
+	case BOMAlways:
+		return newWriter(w, signatureOf(t), true, opts)
+	case BOMNever:
+		return newWriter(w, nil, true, opts)
+	}
+
+	return newWriter(w, nil, false, opts)
+}
+
+// writePending writes the bytes that must be written before the next bytes of
+// the content, such as the BOM
+func (w *Writer) writePending() error {
+	for len(w.pending) > 0 {
+		n, err := w.writer.Write(w.pending)
+		w.pending = w.pending[n:]
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+	}
+
+	return nil
+}
+
+// holds checks if w holds the first bytes of the content until they decide the
+// BOM
+func (w *Writer) holds() bool {
+	return w.strip || w.reject
+}
+
+// decide removes the BOM from the bytes that were held, when w removes it, and
+// makes the rest of them pending. When w rejects the BOM, the error is kept.
+func (w *Writer) decide() error {
+	w.decided = true
+
+	bomType, size := matchSignatureLen(signatures, w.header[:w.held])
+	if w.reject && bomType != Unknown {
+		w.pending = nil
+		w.err = &ExistingBOMError{Type: bomType}
+		return w.err
+	}
+	if !w.strip {
+		size = 0
+	}
+	w.pending = append(append([]byte(nil), w.pending...), w.header[size:w.held]...)
+
+	return nil
+}
+
+// Write is an implementation of io.Writer interface. The returned number of
+// bytes does not include the BOM. When writing the BOM fails, nothing of p is
+// written, and the next call of Write writes the rest of the BOM first.
+//
+// Bytes that are held by a Writer that removes a BOM are counted as written.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	accepted := 0
+	if w.holds() && !w.decided {
+		accepted = copy(w.header[w.held:], p)
+		w.held += accepted
+		if !bomDecided(w.header[:w.held]) {
+			return accepted, nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.writePending(); err != nil {
+		return accepted, err
+	}
+	if accepted == len(p) {
+		return accepted, nil
+	}
+
+	n, err := w.writer.Write(p[accepted:])
+	return accepted + n, err
+}
+
+// ReadFrom is an implementation of io.ReaderFrom interface, so io.Copy to w
+// handles the BOM once, at the beginning of r, and then lets the wrapped
+// writer copy the rest of r in the best way it can. It returns the number of
+// bytes that were read from r, and reaching the end of r is not an error.
+func (w *Writer) ReadFrom(r io.Reader) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	var read int64
+	switch {
+	case w.holds() && !w.decided:
+		header, err := readBOM(r, w.header[:w.held])
+		read = int64(len(header) - w.held)
+		w.held = len(header)
+		if bomDecided(header) {
+			if decideErr := w.decide(); decideErr != nil {
+				return read, decideErr
+			}
+		}
+		if err != nil {
+			// r ended before the rest of it could be copied, and the bytes
+			// that are held, if any, wait for the next write or Flush
+			if err == io.EOF {
+				err = nil
+			}
+			if err == nil && w.decided {
+				err = w.writePending()
+			}
+			return read, err
+		}
+	case len(w.pending) > 0:
+		// the BOM is written only right before the first byte of r
+		n, err := io.ReadAtLeast(r, w.header[:], 1)
+		read = int64(n)
+		if n == 0 {
+			if err == io.EOF {
+				err = nil
+			}
+			return 0, err
+		}
+		w.pending = append(append([]byte(nil), w.pending...), w.header[:n]...)
+		if err != nil && err != io.EOF {
+			return read, err
+		}
+	}
+
+	if err := w.writePending(); err != nil {
+		return read, err
+	}
+
+	copied, err := io.Copy(w.writer, r)
+	return read + copied, err
+}
+
+// Flush writes the bytes that are held while the BOM is not decided yet, when
+// the content ended, such as EF BB. It does nothing when no bytes are held, so
+// a BOM is never written for empty content.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if !w.holds() || w.held == 0 {
+		return nil
+	}
+
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	return w.writePending()
+}
+
+// Close is an implementation of io.Closer interface. It flushes w, and then
+// closes the wrapped writer when it implements io.Closer.
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if closer, ok := w.writer.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,284 @@
+package gobom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		bom    BOMType
+		writes []string
+		want   string
+	}{
+		{"utf8", UTF8, []string{"a,b\n", "c,d\n"}, "\xEF\xBB\xBFa,b\nc,d\n"},
+		{"utf16le", UTF16LE, []string{"a\x00"}, "\xFF\xFEa\x00"},
+		{"empty writes first", UTF8, []string{"", "a"}, "\xEF\xBB\xBFa"},
+		{"nothing written", UTF8, []string{""}, ""},
+		{"unknown", Unknown, []string{"a"}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewWriter(&out, tt.bom)
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				if n != len(s) || err != nil {
+					t.Errorf("Write(%q) = %d, %v, want %d", s, n, err, len(s))
+				}
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+// failingWriter writes up to limit bytes, and then fails
+type failingWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.limit <= 0 {
+		return 0, errWriteFailed
+	}
+	if len(p) > f.limit {
+		n, _ := f.Buffer.Write(p[:f.limit])
+		f.limit = 0
+		return n, errWriteFailed
+	}
+	f.limit -= len(p)
+	return f.Buffer.Write(p)
+}
+
+func TestWriterBOMFailure(t *testing.T) {
+	out := &failingWriter{limit: 1}
+	w := NewWriter(out, UTF8)
+
+	if n, err := w.Write([]byte("a")); n != 0 || err != errWriteFailed {
+		t.Errorf("Write() = %d, %v, want 0, %v", n, err, errWriteFailed)
+	}
+
+	out.limit = 10
+	if n, err := w.Write([]byte("a")); n != 1 || err != nil {
+		t.Errorf("Write() = %d, %v, want 1, nil", n, err)
+	}
+	if out.String() != "\xEF\xBB\xBFa" {
+		t.Errorf("got %q, want the BOM once", out.String())
+	}
+}
+
+func TestStripWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"utf8", []string{"\xEF\xBB\xBFhello"}, "hello"},
+		{"split bom", []string{"\xEF", "\xBB", "\xBFhello"}, "hello"},
+		{"utf32le", []string{"\xFF\xFE", "\x00\x00a\x00\x00\x00"}, "a\x00\x00\x00"},
+		{"no bom", []string{"he", "llo"}, "hello"},
+		{"partial bom", []string{"\xEF\xBB"}, "\xEF\xBB"},
+		{"short utf16le", []string{"\xFF\xFE"}, ""},
+		{"interior bom", []string{"a\xEF\xBB\xBF"}, "a\xEF\xBB\xBF"},
+		{"utf7 variant", []string{"+/v", "9AGE-"}, "+/v9AGE-"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewStripWriter(&out)
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				if n != len(s) || err != nil {
+					t.Errorf("Write(%q) = %d, %v, want %d", s, n, err, len(s))
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BOMPolicy
+		bom    BOMType
+		writes []string
+		want   string
+	}{
+		{"preserve bom", BOMPreserve, UTF8, []string{"\xEF\xBB\xBFa"}, "\xEF\xBB\xBFa"},
+		{"preserve without bom", BOMPreserve, UTF8, []string{"a"}, "a"},
+		{"always adds", BOMAlways, UTF8, []string{"a", "b"}, "\xEF\xBB\xBFab"},
+		{"always keeps one", BOMAlways, UTF8, []string{"\xEF\xBB", "\xBFa"}, "\xEF\xBB\xBFa"},
+		{"always replaces", BOMAlways, UTF8, []string{"\xFF\xFEa"}, "\xEF\xBB\xBFa"},
+		{"always short content", BOMAlways, UTF16LE, []string{"a"}, "\xFF\xFEa"},
+		{"always unknown", BOMAlways, Unknown, []string{"\xEF\xBB\xBFa"}, "a"},
+		{"always empty", BOMAlways, UTF8, nil, ""},
+		{"never", BOMNever, UTF8, []string{"\xEF\xBB\xBFa"}, "a"},
+		{"never without bom", BOMNever, UTF8, []string{"a"}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewPolicyWriter(&out, tt.policy, tt.bom)
+			for _, s := range tt.writes {
+				if _, err := w.Write([]byte(s)); err != nil {
+					t.Errorf("Write(%q) error = %v", s, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterRejectExistingBOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		writer  func(*bytes.Buffer) *Writer
+		writes  []string
+		want    string
+		wantErr BOMType
+	}{
+		{"utf8 on utf8", func(out *bytes.Buffer) *Writer {
+			return NewWriter(out, UTF8, WithRejectExistingBOM())
+		}, []string{"\xEF\xBB", "\xBFa"}, "", UTF8},
+		{"utf16le on utf8", func(out *bytes.Buffer) *Writer {
+			return NewWriter(out, UTF8, WithRejectExistingBOM())
+		}, []string{"\xFF\xFE"}, "", UTF16LE},
+		{"no bom", func(out *bytes.Buffer) *Writer {
+			return NewWriter(out, UTF8, WithRejectExistingBOM())
+		}, []string{"a", "b"}, "\xEF\xBB\xBFab", Unknown},
+		{"short content", func(out *bytes.Buffer) *Writer {
+			return NewWriter(out, UTF8, WithRejectExistingBOM())
+		}, []string{"\xEF"}, "\xEF\xBB\xBF\xEF", Unknown},
+		{"policy always", func(out *bytes.Buffer) *Writer {
+			return NewPolicyWriter(out, BOMAlways, UTF8, WithRejectExistingBOM())
+		}, []string{"\xEF\xBB\xBFa"}, "", UTF8},
+		{"no bom to write", func(out *bytes.Buffer) *Writer {
+			return NewWriter(out, Unknown, WithRejectExistingBOM())
+		}, []string{"\xEF\xBB\xBFa"}, "\xEF\xBB\xBFa", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := tt.writer(&out)
+
+			var err error
+			for _, s := range tt.writes {
+				if _, err = w.Write([]byte(s)); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = w.Close()
+			}
+
+			var existing *ExistingBOMError
+			switch {
+			case tt.wantErr == Unknown && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tt.wantErr != Unknown && (!errors.As(err, &existing) || existing.Type != tt.wantErr):
+				t.Errorf("error = %v, want *ExistingBOMError for %s", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+
+			if tt.wantErr != Unknown {
+				if _, again := w.Write([]byte("b")); again != err {
+					t.Errorf("Write() after error = %v, want %v", again, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterReadFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		writer  func(*bytes.Buffer) *Writer
+		content string
+		want    string
+	}{
+		{"add", func(out *bytes.Buffer) *Writer { return NewWriter(out, UTF8) }, "a,b\n", "\xEF\xBB\xBFa,b\n"},
+		{"add empty", func(out *bytes.Buffer) *Writer { return NewWriter(out, UTF8) }, "", ""},
+		{"strip", func(out *bytes.Buffer) *Writer { return NewStripWriter(out) }, "\xEF\xBB\xBFhello", "hello"},
+		{"strip short", func(out *bytes.Buffer) *Writer { return NewStripWriter(out) }, "\xEF\xBB", "\xEF\xBB"},
+		{"strip utf16le", func(out *bytes.Buffer) *Writer { return NewStripWriter(out) }, "\xFF\xFEa\x00", "a\x00"},
+		{"replace", func(out *bytes.Buffer) *Writer {
+			return NewPolicyWriter(out, BOMAlways, UTF16LE)
+		}, "\xEF\xBB\xBFa", "\xFF\xFEa"},
+		{"preserve", func(out *bytes.Buffer) *Writer {
+			return NewPolicyWriter(out, BOMPreserve, UTF8)
+		}, "\xEF\xBB\xBFa", "\xEF\xBB\xBFa"},
+	}
+
+	for _, tt := range tests {
+		for _, wrap := range []func(io.Reader) io.Reader{
+			func(r io.Reader) io.Reader { return r },
+			iotest.OneByteReader,
+			iotest.DataErrReader,
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				var out bytes.Buffer
+				w := tt.writer(&out)
+
+				n, err := io.Copy(w, wrap(strings.NewReader(tt.content)))
+				if n != int64(len(tt.content)) || err != nil {
+					t.Errorf("io.Copy() = %d, %v, want %d", n, err, len(tt.content))
+				}
+				if err := w.Close(); err != nil {
+					t.Errorf("Close() error = %v", err)
+				}
+				if out.String() != tt.want {
+					t.Errorf("got %q, want %q", out.String(), tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestWriterReadFromThenWrite(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out, UTF8)
+
+	io.Copy(w, strings.NewReader("a"))
+	io.Copy(w, strings.NewReader("b"))
+	w.Write([]byte("c"))
+	if want := "\xEF\xBB\xBFabc"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	w = NewStripWriter(&out)
+	io.Copy(w, strings.NewReader("\xEF"))
+	io.Copy(w, strings.NewReader("\xBB\xBFa"))
+	w.Write([]byte("b"))
+	if want := "ab"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,195 @@
+package gobom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlPrologueSize is the maximum number of bytes that are examined in order to
+// find the XML declaration
+const xmlPrologueSize = 1024
+
+// XMLPrologue holds the encoding information of the beginning of an XML
+// document
+type XMLPrologue struct {
+	// BOM is the BOM the document starts with, or Unknown
+	BOM BOMType
+	// Encoding is the encoding that is declared by the XML declaration, or an
+	// empty string if there is no declaration, or it does not declare one
+	Encoding string
+	// Mismatch is true when the declared encoding does not agree with the BOM,
+	// or with the layout of the bytes when there is no BOM (such as UTF-16
+	// content that declares UTF-8)
+	Mismatch bool
+}
+
+// SniffXML detects the BOM of an XML document, and reads the encoding that is
+// declared by the XML declaration (<?xml version="1.0" encoding="..."?>).
+//
+// The declaration is read according to the BOM, or according to the layout of
+// the first bytes when there is no BOM, as described by Appendix F of the XML
+// specification, so UTF-16 and UTF-32 declarations are read as well.
+func SniffXML(buffer []byte) XMLPrologue {
+	prologue := XMLPrologue{BOM: DetectBOMTypeFromBuffer(buffer)}
+
+	layout := prologue.BOM
+	switch layout {
+	case UTF8, UTF16LE, UTF16BE, UTF32LE, UTF32BE:
+		buffer = buffer[BytesToSkip(buffer):]
+	case Unknown:
+		layout = asciiLayout(buffer)
+	default:
+		// the declaration of other encodings cannot be read, but the BOM
+		// itself is known
+		return prologue
+	}
+
+	prologue.Encoding = xmlDeclaredEncoding(asciiChars(buffer, layout))
+	if prologue.Encoding != "" {
+		prologue.Mismatch = !encodingAgrees(layout, prologue.BOM != Unknown, prologue.Encoding)
+	}
+
+	return prologue
+}
+
+// asciiChars returns the ASCII chars at the beginning of buffer, that is
+// encoded using layout, until the first char that is not ASCII
+func asciiChars(buffer []byte, layout BOMType) []byte {
+	size := 1
+	switch layout {
+	case UTF16LE, UTF16BE:
+		size = 2
+	case UTF32LE, UTF32BE:
+		size = 4
+	}
+
+	if len(buffer) > xmlPrologueSize {
+		buffer = buffer[:xmlPrologueSize]
+	}
+
+	chars := make([]byte, 0, len(buffer)/size)
+	for i := 0; i+size <= len(buffer); i += size {
+		unit := buffer[i : i+size]
+		index := 0
+		if layout.Endianness() == BigEndian {
+			index = size - 1
+		}
+
+		for j, b := range unit {
+			if j != index && b != 0 {
+				return chars
+			}
+		}
+		if unit[index] >= 0x80 {
+			return chars
+		}
+
+		chars = append(chars, unit[index])
+	}
+
+	return chars
+}
+
+// xmlDeclaredEncoding returns the value of the encoding attribute of the XML
+// declaration that prologue starts with
+func xmlDeclaredEncoding(prologue []byte) string {
+	if !bytes.HasPrefix(prologue, []byte("<?xml")) || len(prologue) < 6 || !isXMLSpace(prologue[5]) {
+		return ""
+	}
+
+	end := bytes.Index(prologue, []byte("?>"))
+	if end < 0 {
+		return ""
+	}
+	declaration := prologue[5:end]
+
+	for {
+		i := bytes.Index(declaration, []byte("encoding"))
+		if i < 0 {
+			return ""
+		}
+		before := i == 0 || isXMLSpace(declaration[i-1])
+		declaration = bytes.TrimLeft(declaration[i+len("encoding"):], " \t\r\n")
+		if !before || len(declaration) == 0 || declaration[0] != '=' {
+			continue
+		}
+
+		declaration = bytes.TrimLeft(declaration[1:], " \t\r\n")
+		if len(declaration) == 0 || (declaration[0] != '"' && declaration[0] != '\'') {
+			return ""
+		}
+
+		value := declaration[1:]
+		end := bytes.IndexByte(value, declaration[0])
+		if end < 0 {
+			return ""
+		}
+
+		return string(value[:end])
+	}
+}
+
+// isXMLSpace checks if b is a white space char of XML
+func isXMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// encodingAgrees checks if the encoding name agrees with the layout of the
+// content. When the layout did not come from a BOM, any encoding that is based
+// on ASCII agrees with a UTF-8 layout.
+func encodingAgrees(layout BOMType, fromBOM bool, name string) bool {
+	normalized := normalizeBOMTypeName(name)
+	wide := isWideEncoding(normalized)
+
+	switch layout {
+	case UTF8:
+		if fromBOM {
+			return normalized == "utf8"
+		}
+		return !wide
+	case UTF16LE:
+		return normalized == "utf16" || normalized == "utf16le" || normalized == "ucs2"
+	case UTF16BE:
+		return normalized == "utf16" || normalized == "utf16be" || normalized == "ucs2"
+	case UTF32LE:
+		return normalized == "utf32" || normalized == "utf32le" || normalized == "ucs4"
+	case UTF32BE:
+		return normalized == "utf32" || normalized == "utf32be" || normalized == "ucs4"
+	}
+
+	return false
+}
+
+// isWideEncoding checks if the normalized encoding name is of UTF-16, UTF-32,
+// or their UCS ancestors
+func isWideEncoding(normalized string) bool {
+	return strings.HasPrefix(normalized, "utf16") || strings.HasPrefix(normalized, "utf32") ||
+		strings.HasPrefix(normalized, "ucs")
+}
+
+// NewXMLDecoder creates a new xml.Decoder on top of r, that removes the BOM of
+// r, and decodes UTF-16 and UTF-32 content to UTF-8, so XML documents that
+// Windows applications export parse without a CharsetReader error. The
+// encoding is detected by the BOM, or by the layout of the first bytes, as
+// SniffXML does.
+//
+// Since the content reaches the decoder as UTF-8, the CharsetReader of the
+// returned decoder accepts any UTF-16 and UTF-32 declaration as is, even when
+// it does not agree with the content, and so are US-ASCII declarations, since
+// ASCII is a subset of UTF-8. Any other declared encoding, except UTF-8 which
+// encoding/xml handles by itself, is an error.
+func NewXMLDecoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(&layoutReader{source: r})
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		normalized := normalizeBOMTypeName(charset)
+		if normalized != "usascii" && normalized != "ascii" && !isWideEncoding(normalized) {
+			return nil, fmt.Errorf("gobom: unsupported XML encoding %q", charset)
+		}
+		return input, nil
+	}
+
+	return decoder
+}
@@ -0,0 +1,123 @@
+package gobom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// utf16le encodes ASCII text as UTF-16LE
+func utf16le(s string) []byte {
+	buffer := make([]byte, 0, len(s)*2)
+	for i := 0; i < len(s); i++ {
+		buffer = append(buffer, s[i], 0x00)
+	}
+	return buffer
+}
+
+func TestSniffXML(t *testing.T) {
+	tests := []struct {
+		name   string
+		buffer []byte
+		want   XMLPrologue
+	}{
+		{"empty", nil, XMLPrologue{}},
+		{"no declaration", []byte("<root/>"), XMLPrologue{}},
+		{"no encoding", []byte(`<?xml version="1.0"?><root/>`), XMLPrologue{}},
+		{
+			"utf8",
+			[]byte(`<?xml version="1.0" encoding="UTF-8"?><root/>`),
+			XMLPrologue{Encoding: "UTF-8"},
+		},
+		{
+			"latin1 without bom",
+			[]byte(`<?xml version='1.0' encoding='ISO-8859-1'?>`),
+			XMLPrologue{Encoding: "ISO-8859-1"},
+		},
+		{
+			"utf8 bom",
+			[]byte("\xEF\xBB\xBF<?xml version=\"1.0\" encoding=\"utf-8\"?>"),
+			XMLPrologue{BOM: UTF8, Encoding: "utf-8"},
+		},
+		{
+			"utf8 bom with latin1",
+			[]byte("\xEF\xBB\xBF<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>"),
+			XMLPrologue{BOM: UTF8, Encoding: "ISO-8859-1", Mismatch: true},
+		},
+		{
+			"utf16le bom",
+			append([]byte{0xFF, 0xFE}, utf16le(`<?xml version="1.0" encoding="UTF-16"?>`)...),
+			XMLPrologue{BOM: UTF16LE, Encoding: "UTF-16"},
+		},
+		{
+			"utf16le bom with utf8",
+			append([]byte{0xFF, 0xFE}, utf16le(`<?xml version="1.0" encoding="UTF-8"?>`)...),
+			XMLPrologue{BOM: UTF16LE, Encoding: "UTF-8", Mismatch: true},
+		},
+		{
+			"utf16le without bom",
+			utf16le(`<?xml version="1.0" encoding="utf-8"?>`),
+			XMLPrologue{Encoding: "utf-8", Mismatch: true},
+		},
+		{
+			"utf16 declared without bom",
+			[]byte(`<?xml version="1.0" encoding="UTF-16"?>`),
+			XMLPrologue{Encoding: "UTF-16", Mismatch: true},
+		},
+		{
+			"spaces around equal",
+			[]byte(`<?xml version="1.0" encoding = "windows-1252" ?>`),
+			XMLPrologue{Encoding: "windows-1252"},
+		},
+		{
+			"not a declaration",
+			[]byte(`<?xml-stylesheet href="a.xsl" encoding="utf-8"?>`),
+			XMLPrologue{},
+		},
+	}
+
+	for _, test := range tests {
+		if got := SniffXML(test.buffer); got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNewXMLDecoder(t *testing.T) {
+	document := `<?xml version="1.0" encoding="UTF-16"?><root><name>café</name></root>`
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr bool
+	}{
+		{"utf16le bom", mustEncode(t, document, UTF16LE), false},
+		{"utf16be bom", mustEncode(t, document, UTF16BE), false},
+		{"utf16le", mustEncode(t, document, UTF16LE)[2:], false},
+		{"utf32le bom", mustEncode(t, document, UTF32LE), false},
+		{"utf8 bom", mustEncode(t, strings.Replace(document, "UTF-16", "UTF-8", 1), UTF8), false},
+		{"utf8 declared utf16", []byte(document), false},
+		{"no declaration", []byte("<root><name>café</name></root>"), false},
+		{"us-ascii", []byte(strings.Replace(document, "UTF-16", "US-ASCII", 1)), false},
+		{"ascii", []byte(strings.Replace(document, "UTF-16", "ascii", 1)), false},
+		{"unsupported", []byte(strings.Replace(document, "UTF-16", "KOI8-R", 1)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root struct {
+				Name string `xml:"name"`
+			}
+			err := NewXMLDecoder(bytes.NewReader(tt.content)).Decode(&root)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Decode() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil || root.Name != "café" {
+				t.Errorf("Decode() = %q, %v, want %q", root.Name, err, "café")
+			}
+		})
+	}
+}